@@ -1,6 +1,6 @@
 import axios, { AxiosError } from 'axios';
 import type { LoginRequest, RegisterRequest, AuthResponse, RefreshTokenResponse } from '@/types/auth';
-import type { Book, BookFilters, BooksResponse, Category } from '@/types/book';
+import type { Book, BookFilters, BookPatch, BooksResponse, Category, StockUpdate, Warehouse } from '@/types/book';
 import type { User } from '@/types/user';
 import type { WishlistItem, WishlistResponse } from '@/types/wishlist';
 
@@ -82,11 +82,28 @@ export const booksAPI = {
   create: (book: Partial<Book>) =>
     api.post<{ data: Book }>('/api/v1/books', book),
 
-  update: (id: string, book: Partial<Book>) =>
-    api.put<{ data: Book }>(`/api/v1/books/${id}`, book),
+  // Sends a merge patch so fields the form doesn't show are kept. Pass the
+  // version the edit started from so a concurrent change is rejected
+  update: (id: string, patch: BookPatch, version?: number) =>
+    api.patch<{ data: Book }>(`/api/v1/books/${id}`, patch, {
+      headers: {
+        'Content-Type': 'application/merge-patch+json',
+        ...(version ? { 'If-Match': `"${version}"` } : {}),
+      },
+    }),
 
   delete: (id: string) =>
     api.delete(`/api/v1/books/${id}`),
+
+  // Stock only changes through stock movements, never through update
+  updateStock: (id: string, update: StockUpdate) =>
+    api.patch(`/api/v1/books/${id}/stock`, update),
+};
+
+// Warehouses API
+export const warehousesAPI = {
+  list: () =>
+    api.get<{ data: Warehouse[] }>('/api/v1/warehouses'),
 };
 
 // Categories API
@@ -1,14 +1,14 @@
 import { useState } from 'react';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { Link } from 'react-router-dom';
-import { booksAPI } from '@/lib/api';
+import { booksAPI, warehousesAPI } from '@/lib/api';
 import { useAuthStore } from '@/store/authStore';
 import { Button } from '@/components/ui/button';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { Pencil, Trash2, Plus } from 'lucide-react';
-import type { Book } from '@/types/book';
+import type { Book, BookPatch, StockReason, StockUpdate } from '@/types/book';
 
 export default function ManageBooks() {
   const queryClient = useQueryClient();
@@ -183,30 +183,78 @@ function BookForm({ book, onClose, onSuccess }: BookFormProps) {
     format: book?.format || 'paperback',
   });
 
+  // Editing moves stock at one warehouse instead of setting the total
+  const [stockChange, setStockChange] = useState({
+    quantity: '',
+    warehouse_id: '',
+    reason: 'restock' as StockReason,
+  });
+
+  const [error, setError] = useState('');
+
+  const { data: warehouses } = useQuery({
+    queryKey: ['warehouses'],
+    queryFn: () => warehousesAPI.list().then(res => res.data.data),
+    enabled: !!book,
+  });
+  const defaultWarehouseID = warehouses?.find(w => w.code === 'main')?.id || warehouses?.[0]?.id || '';
+
   const createBookMutation = useMutation({
     mutationFn: (data: Partial<Book>) => booksAPI.create(data),
     onSuccess: () => onSuccess(),
   });
 
   const updateBookMutation = useMutation({
-    mutationFn: (data: Partial<Book>) => booksAPI.update(book!.id, data),
+    mutationFn: async ({ data, stock }: { data: BookPatch; stock?: StockUpdate }) => {
+      await booksAPI.update(book!.id, data, book!.version);
+      if (stock) {
+        try {
+          await booksAPI.updateStock(book!.id, stock);
+        } catch (err: any) {
+          err.stockOnly = true;
+          throw err;
+        }
+      }
+    },
     onSuccess: () => onSuccess(),
+    onError: (err: any) => {
+      if (err.stockOnly) {
+        setError(`The book was saved, but the stock change failed: ${err.response?.data?.error || 'unknown error'}. Close the form and reopen it to try the stock change again.`);
+      } else if (err.response?.status === 412) {
+        setError('This book was changed by someone else. Close the form and reopen it to edit the latest version.');
+      } else {
+        setError(err.response?.data?.error || 'Failed to update book');
+      }
+    },
   });
 
   const handleSubmit = (e: React.FormEvent) => {
     e.preventDefault();
+    setError('');
 
+    const { stock_quantity, ...details } = formData;
     const data = {
-      ...formData,
+      ...details,
       price: parseFloat(formData.price),
-      stock_quantity: parseInt(formData.stock_quantity),
       pages: formData.pages ? parseInt(formData.pages) : undefined,
     };
 
     if (book) {
-      updateBookMutation.mutate(data);
+      const quantity = stockChange.quantity ? parseInt(stockChange.quantity) : 0;
+      const warehouseID = stockChange.warehouse_id || defaultWarehouseID;
+      if (quantity !== 0 && !warehouseID) {
+        setError('Choose a warehouse for the stock change');
+        return;
+      }
+      updateBookMutation.mutate({
+        // A blank page count clears the stored one rather than keeping it
+        data: { ...data, pages: data.pages ?? null },
+        stock: quantity !== 0
+          ? { warehouse_id: warehouseID, quantity, reason: stockChange.reason }
+          : undefined,
+      });
     } else {
-      createBookMutation.mutate(data);
+      createBookMutation.mutate({ ...data, stock_quantity: parseInt(stock_quantity) });
     }
   };
 
@@ -269,17 +317,20 @@ function BookForm({ book, onClose, onSuccess }: BookFormProps) {
                 disabled={isLoading}
               />
             </div>
-            <div className="space-y-2">
-              <Label htmlFor="stock_quantity">Stock Quantity *</Label>
-              <Input
-                id="stock_quantity"
-                type="number"
-                value={formData.stock_quantity}
-                onChange={(e) => setFormData({ ...formData, stock_quantity: e.target.value })}
-                required
-                disabled={isLoading}
-              />
-            </div>
+            {!book && (
+              <div className="space-y-2">
+                <Label htmlFor="stock_quantity">Initial Stock *</Label>
+                <Input
+                  id="stock_quantity"
+                  type="number"
+                  min="0"
+                  value={formData.stock_quantity}
+                  onChange={(e) => setFormData({ ...formData, stock_quantity: e.target.value })}
+                  required
+                  disabled={isLoading}
+                />
+              </div>
+            )}
             <div className="space-y-2">
               <Label htmlFor="pages">Pages</Label>
               <Input
@@ -318,6 +369,53 @@ function BookForm({ book, onClose, onSuccess }: BookFormProps) {
             </div>
           </div>
 
+          {book && (
+            <div className="space-y-2">
+              <Label htmlFor="stock_change">Stock change (currently {book.stock_quantity})</Label>
+              <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+                <Input
+                  id="stock_change"
+                  type="number"
+                  placeholder="e.g. 10 or -2"
+                  value={stockChange.quantity}
+                  onChange={(e) => setStockChange({ ...stockChange, quantity: e.target.value })}
+                  disabled={isLoading}
+                />
+                <select
+                  id="stock_warehouse"
+                  aria-label="Warehouse"
+                  className="w-full h-9 rounded-md border border-input bg-transparent px-3 py-1 text-sm"
+                  value={stockChange.warehouse_id || defaultWarehouseID}
+                  onChange={(e) => setStockChange({ ...stockChange, warehouse_id: e.target.value })}
+                  disabled={isLoading}
+                >
+                  {warehouses?.map((warehouse) => (
+                    <option key={warehouse.id} value={warehouse.id}>{warehouse.name}</option>
+                  ))}
+                </select>
+                <select
+                  id="stock_reason"
+                  aria-label="Reason"
+                  className="w-full h-9 rounded-md border border-input bg-transparent px-3 py-1 text-sm"
+                  value={stockChange.reason}
+                  onChange={(e) => setStockChange({ ...stockChange, reason: e.target.value as StockReason })}
+                  disabled={isLoading}
+                >
+                  <option value="restock">Restock</option>
+                  <option value="sale">Sale</option>
+                  <option value="return">Return</option>
+                  <option value="adjustment">Adjustment</option>
+                </select>
+              </div>
+            </div>
+          )}
+
+          {error && (
+            <div className="text-sm text-red-600 bg-red-50 border border-red-200 rounded-md p-3">
+              {error}
+            </div>
+          )}
+
           <div className="flex gap-2 justify-end">
             <Button type="button" variant="outline" onClick={onClose} disabled={isLoading}>
               Cancel
@@ -1,6 +1,7 @@
 export interface Book {
   id: string;
   isbn: string;
+  isbn10?: string;
   title: string;
   description: string;
   price: number;
@@ -12,6 +13,7 @@ export interface Book {
   format: string;
   cover_image_url?: string;
   metadata?: Record<string, unknown>;
+  version: number;
   created_at: string;
   updated_at: string;
   authors?: Author[];
@@ -46,6 +48,28 @@ export interface Publisher {
   updated_at: string;
 }
 
+export interface Warehouse {
+  id: string;
+  code: string;
+  name: string;
+  address?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export type StockReason = 'restock' | 'sale' | 'return' | 'adjustment';
+
+// BookPatch is a JSON merge patch: absent fields are kept and null clears one
+export type BookPatch = { [K in keyof Book]?: Book[K] | null };
+
+// A stock change at one warehouse; quantity is a delta
+export interface StockUpdate {
+  warehouse_id: string;
+  quantity: number;
+  reason: StockReason;
+  reference_id?: string;
+}
+
 export interface BookFilters {
   title?: string;
   author?: string;
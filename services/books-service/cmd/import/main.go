@@ -0,0 +1,158 @@
+// Command import loads a CSV, NDJSON or ONIX 3.0 catalog file straight into
+// the books database, upserting each row like POST /api/v1/books/import.
+//
+//	go run ./cmd/import [-format csv|ndjson|onix] [-dry-run] [file]
+//
+// With no file, rows are read from stdin. The report is written to stdout as
+// JSON, and the exit status is 1 when any row failed. It uses the same
+// environment variables as the server and expects a migrated database.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/youngermaster/bookstore/services/books-service/internal/config"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository/cache"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository/postgres"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+	postgresql "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	format := flag.String("format", "", "csv, ndjson or onix (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate rows and report what would change without saving")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format csv|ndjson|onix] [-dry-run] [file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	log := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+
+	input, name, err := openInput(flag.Arg(0))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to open import file")
+	}
+	defer input.Close()
+
+	if *format == "" {
+		*format = formatFromName(name)
+	}
+	if *format == "" {
+		log.Fatal().Msg("Set -format when the file isn't .csv, .ndjson, .jsonl, .xml or .onix")
+	}
+
+	cfg := config.Load()
+	db, err := gorm.Open(postgresql.Open(cfg.Database.GetDSN()), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Error),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bookRepo := bookRepository(ctx, db, cfg.Redis, log)
+	authorRepo := postgres.NewAuthorRepository(db)
+	publisherRepo := postgres.NewPublisherRepository(db)
+	categoryRepo := postgres.NewCategoryRepository(db)
+	warehouseRepo := postgres.NewWarehouseRepository(db)
+	recordRepo := postgres.NewRecordReferenceRepository(db)
+
+	bookService := service.NewBookService(bookRepo, categoryRepo, authorRepo, publisherRepo, warehouseRepo)
+	importService := service.NewImportService(bookService, authorRepo, publisherRepo, categoryRepo, recordRepo, warehouseRepo, cfg.Import.Currency)
+
+	report, err := importService.ImportBooks(ctx, *format, input, *dryRun)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Error().Err(err).Msg("Failed to write report")
+		}
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Import stopped")
+	}
+
+	log.Info().
+		Bool("dry_run", report.DryRun).
+		Int("rows", report.Rows).
+		Int("created", report.Created).
+		Int("updated", report.Updated).
+		Int("unchanged", report.Unchanged).
+		Int("failed", report.Failed).
+		Msg("Import finished")
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// openInput opens the named file, or stdin for "" or "-"
+func openInput(path string) (io.ReadCloser, string, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), "", nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, path, nil
+}
+
+func formatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return domain.ImportFormatNDJSON
+	case ".xml", ".onix":
+		return domain.ImportFormatONIX
+	}
+	return ""
+}
+
+// bookRepository goes through the server's Redis cache when it is reachable,
+// so imported books don't stay stale there until their entries expire
+func bookRepository(ctx context.Context, db *gorm.DB, cfg config.RedisConfig, log zerolog.Logger) repository.BookRepository {
+	books := postgres.NewBookRepository(db)
+
+	opts := &redis.Options{Addr: cfg.URL}
+	if strings.Contains(cfg.URL, "://") {
+		parsed, err := redis.ParseURL(cfg.URL)
+		if err != nil {
+			log.Warn().Err(err).Msg("Invalid Redis URL, cached books expire on their own")
+			return books
+		}
+		opts = parsed
+	}
+
+	client := redis.NewClient(opts)
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		log.Warn().Err(err).Msg("Redis unavailable, cached books expire on their own")
+		client.Close()
+		return books
+	}
+	return cache.NewBookRepository(books, client, cfg.CacheTTL, log)
+}
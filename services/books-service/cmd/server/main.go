@@ -1,21 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/youngermaster/bookstore/services/books-service/internal/config"
 	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/grpcserver"
 	"github.com/youngermaster/bookstore/services/books-service/internal/handler"
 	"github.com/youngermaster/bookstore/services/books-service/internal/middleware"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository/cache"
 	"github.com/youngermaster/bookstore/services/books-service/internal/repository/postgres"
 	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+	customJWT "github.com/youngermaster/bookstore/services/books-service/pkg/jwt"
+	"github.com/youngermaster/bookstore/services/books-service/pkg/logclient"
+	booksv1 "github.com/youngermaster/bookstore/services/books-service/proto/books/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	postgresql "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -44,25 +56,67 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to run migrations")
 	}
 
+	// Connect to Redis (the book cache falls back to the database if it is down)
+	redisClient, err := connectRedis(cfg.Redis, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid Redis configuration")
+	}
+	defer redisClient.Close()
+
+	// Initialize JWT verifier (tokens are issued by the users service)
+	jwtVerifier := customJWT.NewVerifier(cfg.JWT.Secret)
+
 	// Initialize repositories
-	bookRepo := postgres.NewBookRepository(db)
-	categoryRepo := postgres.NewCategoryRepository(db)
+	bookRepo := cache.NewBookRepository(postgres.NewBookRepository(db), redisClient, cfg.Redis.CacheTTL, log)
+	categoryRepo := cache.NewCategoryRepository(postgres.NewCategoryRepository(db), bookRepo)
+	authorRepo := cache.NewAuthorRepository(postgres.NewAuthorRepository(db), bookRepo)
+	publisherRepo := cache.NewPublisherRepository(postgres.NewPublisherRepository(db), bookRepo)
+	suggestionRepo := postgres.NewSuggestionRepository(db)
+	reservationRepo := cache.NewReservationRepository(postgres.NewReservationRepository(db), bookRepo)
+	stockMovementRepo := postgres.NewStockMovementRepository(db)
+	warehouseRepo := postgres.NewWarehouseRepository(db)
+	stockAlertRepo := postgres.NewStockAlertRepository(db)
+	recordRepo := postgres.NewRecordReferenceRepository(db)
 
 	// Initialize services
-	bookService := service.NewBookService(bookRepo)
+	bookService := service.NewBookService(bookRepo, categoryRepo, authorRepo, publisherRepo, warehouseRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	authorService := service.NewAuthorService(authorRepo, bookService)
+	publisherService := service.NewPublisherService(publisherRepo, bookService)
+	suggestionService := service.NewSuggestionService(suggestionRepo)
+	reservationService := service.NewReservationService(reservationRepo, bookRepo, warehouseRepo, cfg.Reservation.DefaultTTL, cfg.Reservation.MaxTTL, cfg.Reservation.MaxActive, cfg.Reservation.MaxQuantity)
+	logClient := logclient.New(cfg.Logging.URL, "books-service")
+	inventoryService := service.NewInventoryService(stockMovementRepo, bookRepo, stockAlertRepo, logClient)
+	warehouseService := service.NewWarehouseService(warehouseRepo)
+	importService := service.NewImportService(bookService, authorRepo, publisherRepo, categoryRepo, recordRepo, warehouseRepo, cfg.Import.Currency)
 
 	// Initialize handlers
 	bookHandler := handler.NewBookHandler(bookService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	authorHandler := handler.NewAuthorHandler(authorService)
+	publisherHandler := handler.NewPublisherHandler(publisherService)
+	suggestionHandler := handler.NewSuggestionHandler(suggestionService)
+	reservationHandler := handler.NewReservationHandler(reservationService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	warehouseHandler := handler.NewWarehouseHandler(warehouseService)
+	importHandler := handler.NewImportHandler(importService, cfg.Import.MaxSize, cfg.Import.Timeout)
 
 	// Initialize Fiber app
+	// Bodies are streamed so catalog imports aren't buffered in memory;
+	// every other route is held to the usual limit by BodyLimit
 	app := fiber.New(fiber.Config{
-		ErrorHandler: errorHandler,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		ErrorHandler:      errorHandler,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		BodyLimit:         bodyLimit,
+		StreamRequestBody: true,
 	})
 
 	// Middleware
 	app.Use(recover.New())
+	app.Use(middleware.BodyLimit(bodyLimit, func(c *fiber.Ctx) bool {
+		return c.Method() == fiber.MethodPost && c.Path() == importPath
+	}))
 	app.Use(middleware.CORS())
 	app.Use(middleware.Logger(log))
 
@@ -89,20 +143,86 @@ func main() {
 		return c.JSON(fiber.Map{"status": "ready"})
 	})
 
+	// Book cache counters
+	app.Get("/cache/stats", func(c *fiber.Ctx) error {
+		return c.JSON(bookRepo.Stats())
+	})
+
 	// API routes
 	api := app.Group("/api/v1")
 
+	// Auth middleware for catalog writes; reads stay public
+	requireAuth := middleware.AuthMiddleware(jwtVerifier)
+	canWrite := middleware.RequirePermission("books:write")
+	canDelete := middleware.RequirePermission("books:delete")
+
 	// Book routes
 	books := api.Group("/books")
-	books.Post("/", bookHandler.CreateBook)
+	books.Post("/", requireAuth, canWrite, bookHandler.CreateBook)
+	books.Post("/import", requireAuth, canWrite, importHandler.ImportBooks)
 	books.Get("/", bookHandler.ListBooks)
+	books.Get("/suggest", suggestionHandler.Suggest)
+	books.Get("/isbn/:isbn", bookHandler.GetBookByISBN)
 	books.Get("/:id", bookHandler.GetBook)
-	books.Put("/:id", bookHandler.UpdateBook)
-	books.Delete("/:id", bookHandler.DeleteBook)
-	books.Patch("/:id/stock", bookHandler.UpdateStock)
+	books.Put("/:id", requireAuth, canWrite, bookHandler.UpdateBook)
+	books.Patch("/:id", requireAuth, canWrite, bookHandler.PatchBook)
+	books.Delete("/:id", requireAuth, canDelete, bookHandler.DeleteBook)
+	books.Patch("/:id/stock", requireAuth, canWrite, bookHandler.UpdateStock)
+	books.Get("/:id/stock/movements", requireAuth, canWrite, inventoryHandler.ListMovements)
+	books.Get("/:id/stock/reconciliation", requireAuth, canWrite, inventoryHandler.Reconcile)
+
+	// Stock ledger routes
+	api.Get("/stock/discrepancies", requireAuth, canWrite, inventoryHandler.ListDiscrepancies)
+
+	// Inventory routes for the admin UI
+	api.Get("/inventory/low-stock", requireAuth, canWrite, inventoryHandler.ListLowStock)
+
+	// Category routes
+	categories := api.Group("/categories")
+	categories.Post("/", requireAuth, canWrite, categoryHandler.CreateCategory)
+	categories.Get("/", categoryHandler.ListCategories)
+	categories.Get("/tree", categoryHandler.GetCategoryTree)
+	categories.Get("/slug/:slug", categoryHandler.GetCategoryBySlug)
+	categories.Get("/:id", categoryHandler.GetCategory)
+	categories.Put("/:id", requireAuth, canWrite, categoryHandler.UpdateCategory)
+	categories.Delete("/:id", requireAuth, canDelete, categoryHandler.DeleteCategory)
+
+	// Author routes
+	authors := api.Group("/authors")
+	authors.Post("/", requireAuth, canWrite, authorHandler.CreateAuthor)
+	authors.Get("/", authorHandler.ListAuthors)
+	authors.Get("/:id", authorHandler.GetAuthor)
+	authors.Get("/:id/books", authorHandler.ListAuthorBooks)
+	authors.Put("/:id", requireAuth, canWrite, authorHandler.UpdateAuthor)
+	authors.Delete("/:id", requireAuth, canDelete, authorHandler.DeleteAuthor)
+
+	// Publisher routes
+	publishers := api.Group("/publishers")
+	publishers.Post("/", requireAuth, canWrite, publisherHandler.CreatePublisher)
+	publishers.Get("/", publisherHandler.ListPublishers)
+	publishers.Get("/:id", publisherHandler.GetPublisher)
+	publishers.Get("/:id/books", publisherHandler.ListPublisherBooks)
+	publishers.Put("/:id", requireAuth, canWrite, publisherHandler.UpdatePublisher)
+	publishers.Delete("/:id", requireAuth, canDelete, publisherHandler.DeletePublisher)
+
+	// Warehouse routes
+	warehouses := api.Group("/warehouses")
+	warehouses.Post("/", requireAuth, canWrite, warehouseHandler.CreateWarehouse)
+	warehouses.Get("/", warehouseHandler.ListWarehouses)
+	warehouses.Get("/:id", warehouseHandler.GetWarehouse)
+	warehouses.Put("/:id", requireAuth, canWrite, warehouseHandler.UpdateWarehouse)
+
+	// Reservation routes; any signed-in user can hold stock for checkout
+	reservations := api.Group("/reservations", requireAuth)
+	reservations.Post("/", reservationHandler.CreateReservation)
+	reservations.Get("/:id", reservationHandler.GetReservation)
+	reservations.Post("/:id/confirm", reservationHandler.ConfirmReservation)
+	reservations.Post("/:id/release", reservationHandler.ReleaseReservation)
 
-	// Category routes would go here
-	_ = categoryRepo // TODO: Implement category handler
+	// Return expired holds to stock and watch for low stock in the background
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	go sweepReservations(workerCtx, reservationService, cfg.Reservation.SweepInterval, log)
+	go checkLowStock(workerCtx, inventoryService, cfg.Inventory.LowStockCheckInterval, log)
 
 	// Start server in a goroutine
 	go func() {
@@ -113,12 +233,38 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server in a goroutine
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcserver.Logger(log),
+			grpcserver.Auth(jwtVerifier),
+		),
+	)
+	booksv1.RegisterBookCatalogServer(grpcServer, grpcserver.NewBookCatalogServer(bookService))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		addr := fmt.Sprintf(":%s", cfg.Server.GRPCPort)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to listen for gRPC")
+		}
+		log.Info().Str("addr", addr).Msg("Books gRPC server listening")
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start gRPC server")
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
 	log.Info().Msg("Shutting down server...")
+	stopWorkers()
+	healthServer.Shutdown()
+	grpcServer.GracefulStop()
 	if err := app.Shutdown(); err != nil {
 		log.Error().Err(err).Msg("Server shutdown error")
 	}
@@ -154,17 +300,97 @@ func connectDB(cfg config.DatabaseConfig, log zerolog.Logger) (*gorm.DB, error)
 	return db, nil
 }
 
+// connectRedis builds a Redis client from either a redis:// URL or a host:port
+// address. An unreachable server is only logged since the cache is optional.
+func connectRedis(cfg config.RedisConfig, log zerolog.Logger) (*redis.Client, error) {
+	opts := &redis.Options{Addr: cfg.URL}
+	if strings.Contains(cfg.URL, "://") {
+		parsed, err := redis.ParseURL(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		opts = parsed
+	}
+
+	// Keep timeouts short so a dead Redis doesn't stall requests
+	opts.DialTimeout = 500 * time.Millisecond
+	opts.ReadTimeout = 200 * time.Millisecond
+	opts.WriteTimeout = 200 * time.Millisecond
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Warn().Err(err).Msg("Redis unavailable, book cache disabled until it comes back")
+	} else {
+		log.Info().Msg("Redis connected successfully")
+	}
+
+	return client, nil
+}
+
 func runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&domain.Publisher{},
 		&domain.Author{},
 		&domain.Category{},
 		&domain.Book{},
 		&domain.BookAuthor{},
 		&domain.BookCategory{},
-	)
+		&domain.Warehouse{},
+		&domain.WarehouseStock{},
+		&domain.Reservation{},
+		&domain.StockMovement{},
+		&domain.StockAlert{},
+		&domain.BookRecordReference{},
+	); err != nil {
+		return err
+	}
+
+	if err := postgres.MigrateWarehouses(db); err != nil {
+		return err
+	}
+
+	if err := postgres.MigrateStockLedger(db); err != nil {
+		return err
+	}
+
+	if err := postgres.MigrateISBNs(db); err != nil {
+		return err
+	}
+
+	return postgres.MigrateSearch(db)
 }
 
+// sweepReservations releases expired stock holds every interval until ctx is done
+func sweepReservations(ctx context.Context, reservationService service.ReservationService, interval time.Duration, log zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			restocked, err := reservationService.ReleaseExpired(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to release expired reservations")
+				continue
+			}
+			if restocked > 0 {
+				log.Info().Int("books", restocked).Msg("Released expired reservations")
+			}
+		}
+	}
+}
+
+// bodyLimit caps request bodies on every route but the catalog import
+const bodyLimit = 4 << 20
+
+// importPath is the route that streams its body into a catalog import
+const importPath = "/api/v1/books/import"
+
 func errorHandler(c *fiber.Ctx, err error) error {
 	code := fiber.StatusInternalServerError
 	if e, ok := err.(*fiber.Error); ok {
@@ -175,3 +401,24 @@ func errorHandler(c *fiber.Ctx, err error) error {
 		"error": err.Error(),
 	})
 }
+
+// checkLowStock raises low-stock alerts every interval until ctx is done
+func checkLowStock(ctx context.Context, inventoryService service.InventoryService, interval time.Duration, log zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			opened, err := inventoryService.CheckLowStock(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to check low stock")
+			}
+			if opened > 0 {
+				log.Warn().Int64("books", opened).Msg("Books fell to their reorder threshold")
+			}
+		}
+	}
+}
@@ -4,13 +4,20 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the books service
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	JWT         JWTConfig
+	Reservation ReservationConfig
+	Inventory   InventoryConfig
+	Import      ImportConfig
+	Logging     LoggingConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -32,9 +39,46 @@ type DatabaseConfig struct {
 
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
+	URL      string
+	CacheTTL time.Duration
+}
+
+// ReservationConfig holds how long stock holds last, how often expired holds
+// are swept back into stock, and how many holds and copies per hold each user
+// may have
+type ReservationConfig struct {
+	DefaultTTL    time.Duration
+	MaxTTL        time.Duration
+	SweepInterval time.Duration
+	MaxActive     int
+	MaxQuantity   int
+}
+
+// InventoryConfig holds how often stock is checked against reorder thresholds
+type InventoryConfig struct {
+	LowStockCheckInterval time.Duration
+}
+
+// ImportConfig holds how large a catalog upload to POST /books/import may be
+// and how long the server waits for it. Uploads are imported as they are read.
+// Currency is the store's currency; ONIX prices in any other are ignored.
+type ImportConfig struct {
+	MaxSize  int
+	Timeout  time.Duration
+	Currency string
+}
+
+// LoggingConfig holds the location of the logging service, which receives
+// low-stock alerts
+type LoggingConfig struct {
 	URL string
 }
 
+// JWTConfig holds the configuration used to verify tokens issued by the users service
+type JWTConfig struct {
+	Secret string
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -52,7 +96,29 @@ func Load() *Config {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		Redis: RedisConfig{
-			URL: getEnv("REDIS_URL", "localhost:6379"),
+			URL:      getEnv("REDIS_URL", "localhost:6379"),
+			CacheTTL: time.Duration(getEnvAsInt("CACHE_TTL_SECONDS", 300)) * time.Second,
+		},
+		JWT: JWTConfig{
+			Secret: getEnv("JWT_SECRET", "dev_jwt_secret_change_in_production"),
+		},
+		Reservation: ReservationConfig{
+			DefaultTTL:    time.Duration(getEnvAsInt("RESERVATION_TTL_SECONDS", 900)) * time.Second,
+			MaxTTL:        time.Duration(getEnvAsInt("RESERVATION_MAX_TTL_SECONDS", 3600)) * time.Second,
+			SweepInterval: time.Duration(getEnvAsInt("RESERVATION_SWEEP_INTERVAL_SECONDS", 30)) * time.Second,
+			MaxActive:     getEnvAsInt("RESERVATION_MAX_ACTIVE", 10),
+			MaxQuantity:   getEnvAsInt("RESERVATION_MAX_QUANTITY", 10),
+		},
+		Inventory: InventoryConfig{
+			LowStockCheckInterval: time.Duration(getEnvAsInt("LOW_STOCK_CHECK_INTERVAL_SECONDS", 60)) * time.Second,
+		},
+		Import: ImportConfig{
+			MaxSize:  getEnvAsInt("IMPORT_MAX_SIZE_MB", 100) << 20,
+			Timeout:  time.Duration(getEnvAsInt("IMPORT_TIMEOUT_SECONDS", 600)) * time.Second,
+			Currency: strings.ToUpper(getEnv("STORE_CURRENCY", "USD")),
+		},
+		Logging: LoggingConfig{
+			URL: getEnv("LOGGING_SERVICE_URL", "http://localhost:8084"),
 		},
 	}
 }
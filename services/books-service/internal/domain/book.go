@@ -8,24 +8,34 @@ import (
 
 // Book represents a book in the catalog
 type Book struct {
-	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	ISBN            string     `json:"isbn" gorm:"uniqueIndex;not null"`
-	Title           string     `json:"title" gorm:"size:500;not null"`
-	Description     string     `json:"description" gorm:"type:text"`
-	PublisherID     *uuid.UUID `json:"publisher_id" gorm:"type:uuid"`
-	Publisher       *Publisher `json:"publisher,omitempty" gorm:"foreignKey:PublisherID"`
-	PublicationDate *time.Time `json:"publication_date"`
-	Language        string     `json:"language" gorm:"size:10;default:'en'"`
-	Pages           int        `json:"pages"`
-	Format          string     `json:"format" gorm:"size:50"` // hardcover, paperback, ebook
-	Price           float64    `json:"price" gorm:"not null;check:price >= 0"`
-	StockQuantity   int        `json:"stock_quantity" gorm:"not null;default:0;check:stock_quantity >= 0"`
-	CoverImageURL   string     `json:"cover_image_url" gorm:"type:text"`
-	Metadata        string     `json:"metadata" gorm:"type:jsonb"` // flexible additional data
-	Authors         []Author   `json:"authors,omitempty" gorm:"many2many:book_authors;"`
-	Categories      []Category `json:"categories,omitempty" gorm:"many2many:book_categories;"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ISBN             string     `json:"isbn" gorm:"uniqueIndex;not null"`              // canonical ISBN-13 without hyphens
+	ISBN10           string     `json:"isbn10,omitempty" gorm:"column:isbn10;size:10"` // only for 978- ISBNs
+	Title            string     `json:"title" gorm:"size:500;not null"`
+	Description      string     `json:"description" gorm:"type:text"`
+	PublisherID      *uuid.UUID `json:"publisher_id" gorm:"type:uuid"`
+	Publisher        *Publisher `json:"publisher,omitempty" gorm:"foreignKey:PublisherID"`
+	PublicationDate  *time.Time `json:"publication_date"`
+	Language         string     `json:"language" gorm:"size:10;default:'en'"`
+	Pages            int        `json:"pages"`
+	Format           string     `json:"format" gorm:"size:50"` // hardcover, paperback, ebook
+	Price            float64    `json:"price" gorm:"not null;check:price >= 0"`
+	StockQuantity    int        `json:"stock_quantity" gorm:"not null;default:0;check:stock_quantity >= 0"`
+	ReorderThreshold *int       `json:"reorder_threshold" gorm:"check:reorder_threshold >= 0"` // overrides the category threshold
+	CoverImageURL    string     `json:"cover_image_url" gorm:"type:text"`
+	Metadata         string     `json:"metadata" gorm:"type:jsonb"` // flexible additional data
+	Authors          []Author   `json:"authors,omitempty" gorm:"many2many:book_authors;"`
+	Categories       []Category `json:"categories,omitempty" gorm:"many2many:book_categories;"`
+	Version          int        `json:"version" gorm:"not null;default:1"` // bumped on every edit; stock changes don't count
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Populated only by full-text search queries
+	SearchRank float64 `json:"search_rank,omitempty" gorm:"->;-:migration"`
+	Highlight  string  `json:"highlight,omitempty" gorm:"->;-:migration"`
+
+	// Populated only when availability per warehouse is requested
+	Availability []WarehouseStock `json:"availability,omitempty" gorm:"-"`
 }
 
 // TableName specifies the table name for Book
@@ -33,6 +43,30 @@ func (Book) TableName() string {
 	return "books"
 }
 
+// Sort fields accepted by book listings
+const (
+	BookSortCreatedAt       = "created_at"
+	BookSortPrice           = "price"
+	BookSortTitle           = "title"
+	BookSortPublicationDate = "publication_date"
+	BookSortRelevance       = "relevance"
+)
+
+// BookSort is the order of a book listing
+type BookSort struct {
+	Field      string
+	Descending bool
+}
+
+// BookPatch is a validated partial update of a book. Columns maps each column
+// to set to its new value, with nil for NULL. AuthorIDs and CategoryIDs
+// replace the book's links in order when non-nil; empty lists remove them all.
+type BookPatch struct {
+	Columns     map[string]interface{}
+	AuthorIDs   []uuid.UUID
+	CategoryIDs []uuid.UUID
+}
+
 // BookAuthor represents the many-to-many relationship between books and authors
 type BookAuthor struct {
 	BookID      uuid.UUID `gorm:"type:uuid;primaryKey"`
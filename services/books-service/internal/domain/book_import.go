@@ -0,0 +1,56 @@
+package domain
+
+// Catalog import formats
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+	ImportFormatONIX   = "onix" // ONIX for Books 3.0 with reference tags
+)
+
+// BookImportRow is one book of a catalog import. Nil fields were left out of
+// the row, and an existing book keeps its current value for them. The
+// publisher and authors are matched by name and categories by slug or name;
+// missing ones are created. Authors are credited in the order listed.
+type BookImportRow struct {
+	Line             int      `json:"-"`
+	RecordReference  string   `json:"-"` // the feed's own ID for the record, if it has one
+	ISBN             string   `json:"isbn"`
+	Title            *string  `json:"title"`
+	Description      *string  `json:"description"`
+	Publisher        *string  `json:"publisher"`
+	PublicationDate  *string  `json:"publication_date"` // YYYY-MM-DD or RFC 3339
+	Language         *string  `json:"language"`
+	Pages            *int     `json:"pages"`
+	Format           *string  `json:"format"`
+	Price            *float64 `json:"price"`
+	StockQuantity    *int     `json:"stock_quantity"` // the difference is adjusted in the default warehouse
+	ReorderThreshold *int     `json:"reorder_threshold"`
+	CoverImageURL    *string  `json:"cover_image_url"`
+	Authors          []string `json:"authors"`
+	Categories       []string `json:"categories"`
+}
+
+// ImportRowError reports a row that couldn't be imported. Created lists the
+// publishers, authors and categories the row created before it failed; they
+// are kept and counted in the report.
+type ImportRowError struct {
+	Line    int      `json:"line"`
+	ISBN    string   `json:"isbn,omitempty"`
+	Error   string   `json:"error"`
+	Created []string `json:"created,omitempty"`
+}
+
+// ImportReport summarizes a catalog import. In a dry run the counts are what
+// the import would have done.
+type ImportReport struct {
+	DryRun            bool             `json:"dry_run"`
+	Rows              int              `json:"rows"`
+	Created           int              `json:"created"`
+	Updated           int              `json:"updated"`
+	Unchanged         int              `json:"unchanged"`
+	Failed            int              `json:"failed"`
+	AuthorsCreated    int              `json:"authors_created"`
+	PublishersCreated int              `json:"publishers_created"`
+	CategoriesCreated int              `json:"categories_created"`
+	Errors            []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+// Facet names accepted by the facets= list parameter
+const (
+	FacetCategory  = "category"
+	FacetAuthor    = "author"
+	FacetPublisher = "publisher"
+	FacetFormat    = "format"
+	FacetLanguage  = "language"
+	FacetPrice     = "price"
+)
+
+// AllFacets lists every supported facet in response order
+var AllFacets = []string{FacetCategory, FacetAuthor, FacetPublisher, FacetFormat, FacetLanguage, FacetPrice}
+
+// FacetValue is the number of matching books sharing one value of a facet.
+// Value is an ID for categories, authors and publishers, with the name in Label.
+type FacetValue struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucket counts matching books with Min <= price < Max.
+// Max is nil for the last, open-ended bucket.
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+// Facets holds the requested facet counts of a book listing
+type Facets struct {
+	Categories []FacetValue  `json:"categories,omitempty"`
+	Authors    []FacetValue  `json:"authors,omitempty"`
+	Publishers []FacetValue  `json:"publishers,omitempty"`
+	Formats    []FacetValue  `json:"formats,omitempty"`
+	Languages  []FacetValue  `json:"languages,omitempty"`
+	Price      []PriceBucket `json:"price,omitempty"`
+}
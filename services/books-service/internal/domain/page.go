@@ -0,0 +1,32 @@
+package domain
+
+import "github.com/google/uuid"
+
+// PageRequest selects one page of a book listing, either by offset or by an
+// opaque cursor from a previous page. A cursor takes precedence over Offset.
+type PageRequest struct {
+	Limit        int
+	Offset       int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// Keyset is a decoded cursor: the sort key and ID of the row a page continues
+// from. Before pages walk backwards towards the start of the listing.
+type Keyset struct {
+	Sort   BookSort
+	Value  interface{}
+	ID     uuid.UUID
+	Before bool
+}
+
+// BookPage is one page of a book listing. Total is nil when it wasn't counted,
+// and a cursor is empty when there is no page in that direction.
+type BookPage struct {
+	Books      []Book
+	Total      *int64
+	Limit      int
+	Offset     int
+	NextCursor string
+	PrevCursor string
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookRecordReference ties a feed's record reference, such as an ONIX
+// RecordReference, to the book it describes, so a later update of the record
+// finds the book even if the ISBN it carries has changed
+type BookRecordReference struct {
+	Reference string    `json:"reference" gorm:"primaryKey;size:255"`
+	BookID    uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"`
+	Book      *Book     `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for BookRecordReference
+func (BookRecordReference) TableName() string {
+	return "book_record_references"
+}
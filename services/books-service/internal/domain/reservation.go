@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation statuses. A held reservation has already taken its quantity out
+// of the book's stock at its warehouse; releasing or expiring it puts the
+// quantity back.
+const (
+	ReservationHeld      = "held"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation is a temporary hold on book stock during checkout
+type Reservation struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BookID      uuid.UUID `json:"book_id" gorm:"type:uuid;not null;index"`
+	Book        *Book     `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	WarehouseID uuid.UUID `json:"warehouse_id" gorm:"type:uuid;index"`
+	Quantity    int       `json:"quantity" gorm:"not null;check:quantity > 0"`
+	Status      string    `json:"status" gorm:"size:20;not null;default:'held'"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index:idx_reservations_held_expiry,where:status = 'held'"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Reservation
+func (Reservation) TableName() string {
+	return "reservations"
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockAlert records a book falling to or below its reorder threshold. The
+// alert stays open until the book is restocked above the threshold, so a book
+// only alerts once each time it runs low.
+type StockAlert struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BookID        uuid.UUID  `json:"book_id" gorm:"type:uuid;not null;uniqueIndex:idx_stock_alerts_open_book,where:resolved_at IS NULL"`
+	Book          *Book      `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	StockQuantity int        `json:"stock_quantity" gorm:"not null"`
+	Threshold     int        `json:"threshold" gorm:"not null"`
+	NotifiedAt    *time.Time `json:"notified_at"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for StockAlert
+func (StockAlert) TableName() string {
+	return "stock_alerts"
+}
+
+// LowStockItem is a book at or below its effective reorder threshold: its own,
+// or else the highest among its categories
+type LowStockItem struct {
+	BookID        uuid.UUID  `json:"book_id"`
+	Title         string     `json:"title"`
+	ISBN          string     `json:"isbn"`
+	StockQuantity int        `json:"stock_quantity"`
+	Threshold     int        `json:"threshold"`
+	AlertedAt     *time.Time `json:"alerted_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock movement reasons. Reservation movements are written by the
+// reservation system; the others can be recorded through the stock endpoint.
+const (
+	StockReasonRestock     = "restock"
+	StockReasonSale        = "sale"
+	StockReasonReturn      = "return"
+	StockReasonAdjustment  = "adjustment"
+	StockReasonReservation = "reservation"
+)
+
+// StockMovement is one append-only ledger entry explaining a change to a
+// book's stock. The ledger sum for a book should equal its StockQuantity.
+type StockMovement struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BookID      uuid.UUID  `json:"book_id" gorm:"type:uuid;not null;index:idx_stock_movements_book,priority:1"`
+	WarehouseID *uuid.UUID `json:"warehouse_id" gorm:"type:uuid;index"`
+	Delta       int        `json:"delta" gorm:"not null"`
+	Reason      string     `json:"reason" gorm:"size:20;not null"`
+	ActorID     *uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+	ReferenceID string     `json:"reference_id,omitempty" gorm:"size:100"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;index:idx_stock_movements_book,priority:2"`
+}
+
+// TableName specifies the table name for StockMovement
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// StockReconciliation compares a book's stock with the sum of its ledger
+type StockReconciliation struct {
+	BookID         uuid.UUID `json:"book_id"`
+	StockQuantity  int       `json:"stock_quantity"`
+	LedgerQuantity int       `json:"ledger_quantity"`
+	Difference     int       `json:"difference"`
+	Consistent     bool      `json:"consistent"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Suggestion types returned by autocomplete
+const (
+	SuggestionTypeTitle    = "title"
+	SuggestionTypeAuthor   = "author"
+	SuggestionTypeCategory = "category"
+)
+
+// Suggestion is a single autocomplete match
+type Suggestion struct {
+	Type  string    `json:"-"`
+	ID    uuid.UUID `json:"id"`
+	Text  string    `json:"text"`
+	Score float64   `json:"score"`
+}
+
+// Suggestions groups autocomplete matches by type
+type Suggestions struct {
+	Titles     []Suggestion `json:"titles"`
+	Authors    []Suggestion `json:"authors"`
+	Categories []Suggestion `json:"categories"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWarehouseCode is the warehouse created on migration. It holds the
+// stock books had before warehouses existed and the initial stock of new books.
+const DefaultWarehouseCode = "main"
+
+// Warehouse is a location books are stocked and shipped from
+type Warehouse struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Code      string    `json:"code" gorm:"size:50;uniqueIndex;not null"`
+	Name      string    `json:"name" gorm:"size:255;not null"`
+	Address   string    `json:"address" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Warehouse
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// WarehouseStock is a book's stock level at one warehouse. A book's
+// StockQuantity is the sum of its levels and is kept in step by the database.
+type WarehouseStock struct {
+	BookID      uuid.UUID  `json:"book_id" gorm:"type:uuid;primaryKey"`
+	Book        *Book      `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	WarehouseID uuid.UUID  `json:"warehouse_id" gorm:"type:uuid;primaryKey;index"`
+	Warehouse   *Warehouse `json:"warehouse,omitempty" gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Quantity    int        `json:"quantity" gorm:"not null;default:0;check:quantity >= 0"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for WarehouseStock
+func (WarehouseStock) TableName() string {
+	return "warehouse_stock"
+}
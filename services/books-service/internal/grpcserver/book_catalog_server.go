@@ -0,0 +1,281 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+	booksv1 "github.com/youngermaster/bookstore/services/books-service/proto/books/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BookCatalogServer implements the BookCatalog gRPC service on top of BookService
+type BookCatalogServer struct {
+	booksv1.UnimplementedBookCatalogServer
+	bookService service.BookService
+}
+
+// NewBookCatalogServer creates a new instance of BookCatalogServer
+func NewBookCatalogServer(bookService service.BookService) *BookCatalogServer {
+	return &BookCatalogServer{
+		bookService: bookService,
+	}
+}
+
+// GetBook handles BookCatalog.GetBook
+func (s *BookCatalogServer) GetBook(ctx context.Context, req *booksv1.GetBookRequest) (*booksv1.Book, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid book ID")
+	}
+
+	book, err := s.bookService.GetBook(ctx, id)
+	if err != nil {
+		return nil, bookError(err, "failed to get book")
+	}
+
+	return toProtoBook(book), nil
+}
+
+// GetBookByISBN handles BookCatalog.GetBookByISBN
+func (s *BookCatalogServer) GetBookByISBN(ctx context.Context, req *booksv1.GetBookByISBNRequest) (*booksv1.Book, error) {
+	if req.GetIsbn() == "" {
+		return nil, status.Error(codes.InvalidArgument, "isbn is required")
+	}
+
+	book, err := s.bookService.GetBookByISBN(ctx, req.GetIsbn())
+	if err != nil {
+		return nil, bookError(err, "failed to get book")
+	}
+
+	return toProtoBook(book), nil
+}
+
+// ListBooks handles BookCatalog.ListBooks
+func (s *BookCatalogServer) ListBooks(ctx context.Context, req *booksv1.ListBooksRequest) (*booksv1.ListBooksResponse, error) {
+	filters := make(map[string]interface{})
+	for key, value := range map[string]string{
+		"category_id":  req.GetCategoryId(),
+		"author_id":    req.GetAuthorId(),
+		"publisher_id": req.GetPublisherId(),
+	} {
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s", key)
+		}
+		filters[key] = id
+	}
+	if req.GetFormat() != "" {
+		filters["format"] = req.GetFormat()
+	}
+	if req.GetLanguage() != "" {
+		filters["language"] = req.GetLanguage()
+	}
+	if req.GetTitle() != "" {
+		filters["title"] = req.GetTitle()
+	}
+	if query := strings.TrimSpace(req.GetQuery()); query != "" {
+		filters["q"] = query
+	}
+	if req.MinPrice != nil {
+		filters["min_price"] = req.GetMinPrice()
+	}
+	if req.MaxPrice != nil {
+		filters["max_price"] = req.GetMaxPrice()
+	}
+	if req.InStock != nil {
+		filters["in_stock"] = req.GetInStock()
+	}
+	if len(req.GetIsbns()) > 0 {
+		filters["isbn"] = req.GetIsbns()
+	}
+	for key, value := range map[string]string{
+		"published_from": req.GetPublishedFrom(),
+		"published_to":   req.GetPublishedTo(),
+	} {
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s", key)
+		}
+		filters[key] = date
+	}
+
+	sort, err := service.ParseBookSort(req.GetSort(), req.GetOrder())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if sort != nil {
+		filters["sort"] = *sort
+	}
+
+	page := domain.PageRequest{
+		Limit:        int(req.GetLimit()),
+		Offset:       int(req.GetOffset()),
+		Cursor:       req.GetCursor(),
+		IncludeTotal: req.GetCursor() == "",
+	}
+	if req.IncludeTotal != nil {
+		page.IncludeTotal = req.GetIncludeTotal()
+	}
+
+	result, err := s.bookService.ListBooks(ctx, page, filters)
+	if err != nil {
+		return nil, bookError(err, "failed to list books")
+	}
+
+	resp := &booksv1.ListBooksResponse{
+		Books:      make([]*booksv1.Book, 0, len(result.Books)),
+		Total:      result.Total,
+		Limit:      int32(result.Limit),
+		Offset:     int32(result.Offset),
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	}
+	for i := range result.Books {
+		resp.Books = append(resp.Books, toProtoBook(&result.Books[i]))
+	}
+	return resp, nil
+}
+
+// BatchGetBooks handles BookCatalog.BatchGetBooks
+func (s *BookCatalogServer) BatchGetBooks(ctx context.Context, req *booksv1.BatchGetBooksRequest) (*booksv1.BatchGetBooksResponse, error) {
+	ids := make([]uuid.UUID, 0, len(req.GetIds()))
+	for _, value := range req.GetIds() {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid book ID %q", value)
+		}
+		ids = append(ids, id)
+	}
+
+	books, err := s.bookService.GetBooksByIDs(ctx, ids)
+	if err != nil {
+		return nil, bookError(err, "failed to get books")
+	}
+
+	found := make(map[uuid.UUID]bool, len(books))
+	resp := &booksv1.BatchGetBooksResponse{
+		Books: make([]*booksv1.Book, 0, len(books)),
+	}
+	for i := range books {
+		found[books[i].ID] = true
+		resp.Books = append(resp.Books, toProtoBook(&books[i]))
+	}
+	for _, id := range ids {
+		if !found[id] {
+			resp.MissingIds = append(resp.MissingIds, id.String())
+		}
+	}
+	return resp, nil
+}
+
+// AdjustStock handles BookCatalog.AdjustStock and returns the updated book
+func (s *BookCatalogServer) AdjustStock(ctx context.Context, req *booksv1.AdjustStockRequest) (*booksv1.Book, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid book ID")
+	}
+	warehouseID, err := uuid.Parse(req.GetWarehouseId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid warehouse ID")
+	}
+
+	movement := &domain.StockMovement{
+		BookID:      id,
+		WarehouseID: &warehouseID,
+		Delta:       int(req.GetDelta()),
+		Reason:      req.GetReason(),
+		ReferenceID: req.GetReferenceId(),
+	}
+	if claims, ok := claimsFromContext(ctx); ok {
+		movement.ActorID = &claims.UserID
+	}
+
+	if err := s.bookService.UpdateBookStock(ctx, movement); err != nil {
+		return nil, bookError(err, "failed to update stock")
+	}
+
+	book, err := s.bookService.GetBook(ctx, id)
+	if err != nil {
+		return nil, bookError(err, "failed to get book")
+	}
+
+	return toProtoBook(book), nil
+}
+
+// bookError maps book service errors to gRPC status errors
+func bookError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrBookNotFound):
+		return status.Error(codes.NotFound, "book not found")
+	case errors.Is(err, service.ErrWarehouseNotFound):
+		return status.Error(codes.NotFound, "warehouse not found")
+	case errors.Is(err, service.ErrInsufficientStock):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidReason):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, fallback)
+}
+
+// toProtoBook converts a domain book into its protobuf representation
+func toProtoBook(book *domain.Book) *booksv1.Book {
+	pb := &booksv1.Book{
+		Id:            book.ID.String(),
+		Isbn:          book.ISBN,
+		Isbn10:        book.ISBN10,
+		Title:         book.Title,
+		Description:   book.Description,
+		Language:      book.Language,
+		Pages:         int32(book.Pages),
+		Format:        book.Format,
+		Price:         book.Price,
+		StockQuantity: int32(book.StockQuantity),
+		CoverImageUrl: book.CoverImageURL,
+		SearchRank:    book.SearchRank,
+		Highlight:     book.Highlight,
+		CreatedAt:     timestamppb.New(book.CreatedAt),
+		UpdatedAt:     timestamppb.New(book.UpdatedAt),
+	}
+
+	if book.PublisherID != nil {
+		pb.PublisherId = book.PublisherID.String()
+	}
+	if book.Publisher != nil {
+		pb.Publisher = &booksv1.Publisher{
+			Id:   book.Publisher.ID.String(),
+			Name: book.Publisher.Name,
+		}
+	}
+	if book.PublicationDate != nil {
+		pb.PublicationDate = timestamppb.New(*book.PublicationDate)
+	}
+	for _, author := range book.Authors {
+		pb.Authors = append(pb.Authors, &booksv1.Author{
+			Id:   author.ID.String(),
+			Name: author.Name,
+		})
+	}
+	for _, category := range book.Categories {
+		pb.Categories = append(pb.Categories, &booksv1.Category{
+			Id:   category.ID.String(),
+			Name: category.Name,
+			Slug: category.Slug,
+		})
+	}
+
+	return pb
+}
@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	customJWT "github.com/youngermaster/bookstore/services/books-service/pkg/jwt"
+	booksv1 "github.com/youngermaster/bookstore/services/books-service/proto/books/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodPermissions lists the RPCs that require a token and the permission they need.
+// Methods not listed are public, matching the HTTP API.
+var methodPermissions = map[string]string{
+	booksv1.BookCatalog_AdjustStock_FullMethodName: "books:write",
+}
+
+// claimsKey is the context key under which Auth stores the caller's claims
+type claimsKey struct{}
+
+// claimsFromContext returns the claims of the authenticated caller, if any
+func claimsFromContext(ctx context.Context) (*customJWT.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*customJWT.Claims)
+	return claims, ok
+}
+
+// Logger creates an interceptor that logs unary RPCs
+func Logger(log zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		log.Info().
+			Str("method", info.FullMethod).
+			Str("code", status.Code(err).String()).
+			Dur("duration", time.Since(start)).
+			Msg("gRPC request")
+
+		return resp, err
+	}
+}
+
+// Auth creates an interceptor that validates the bearer token in the
+// "authorization" metadata for methods listed in methodPermissions
+func Auth(verifier *customJWT.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		required, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "no authorization metadata")
+		}
+
+		tokenString := strings.TrimPrefix(values[0], "Bearer ")
+		if tokenString == values[0] {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization format")
+		}
+
+		claims, err := verifier.ValidateToken(tokenString)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+
+		for _, granted := range claims.Permissions {
+			if granted == required {
+				return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+			}
+		}
+
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+}
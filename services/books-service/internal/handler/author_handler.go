@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// AuthorHandler handles HTTP requests for authors
+type AuthorHandler struct {
+	authorService service.AuthorService
+}
+
+// NewAuthorHandler creates a new instance of AuthorHandler
+func NewAuthorHandler(authorService service.AuthorService) *AuthorHandler {
+	return &AuthorHandler{
+		authorService: authorService,
+	}
+}
+
+// CreateAuthor handles POST /api/v1/authors
+func (h *AuthorHandler) CreateAuthor(c *fiber.Ctx) error {
+	var author domain.Author
+	if err := c.BodyParser(&author); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.authorService.CreateAuthor(c.Context(), &author); err != nil {
+		return authorError(c, err, "Failed to create author")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(author)
+}
+
+// ListAuthors handles GET /api/v1/authors
+func (h *AuthorHandler) ListAuthors(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	authors, total, err := h.authorService.ListAuthors(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list authors",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   authors,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetAuthor handles GET /api/v1/authors/:id
+func (h *AuthorHandler) GetAuthor(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+
+	author, err := h.authorService.GetAuthor(c.Context(), id)
+	if err != nil {
+		return authorError(c, err, "Failed to get author")
+	}
+
+	return c.JSON(author)
+}
+
+// UpdateAuthor handles PUT /api/v1/authors/:id
+func (h *AuthorHandler) UpdateAuthor(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+
+	var author domain.Author
+	if err := c.BodyParser(&author); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	author.ID = id
+
+	if err := h.authorService.UpdateAuthor(c.Context(), &author); err != nil {
+		return authorError(c, err, "Failed to update author")
+	}
+
+	return c.JSON(author)
+}
+
+// DeleteAuthor handles DELETE /api/v1/authors/:id
+func (h *AuthorHandler) DeleteAuthor(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+
+	if err := h.authorService.DeleteAuthor(c.Context(), id); err != nil {
+		return authorError(c, err, "Failed to delete author")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListAuthorBooks handles GET /api/v1/authors/:id/books
+// Accepts the same filters as GET /api/v1/books.
+func (h *AuthorHandler) ListAuthorBooks(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filters, err := parseBookFilters(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.authorService.ListAuthorBooks(c.Context(), id, page, filters)
+	if err != nil {
+		return authorError(c, err, "Failed to list author books")
+	}
+
+	return c.JSON(bookPageResponse(result, page))
+}
+
+// authorError maps author service errors to HTTP responses
+func authorError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrAuthorNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Author not found",
+		})
+	case errors.Is(err, service.ErrAuthorHasBooks):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrInvalidCursor):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
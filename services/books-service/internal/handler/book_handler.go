@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -10,6 +14,9 @@ import (
 	"github.com/youngermaster/bookstore/services/books-service/internal/service"
 )
 
+// mergePatchMediaType is the body type accepted when patching a book
+const mergePatchMediaType = "application/merge-patch+json"
+
 // BookHandler handles HTTP requests for books
 type BookHandler struct {
 	bookService service.BookService
@@ -47,10 +54,13 @@ func (h *BookHandler) CreateBook(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, bookETag(&book))
 	return c.Status(fiber.StatusCreated).JSON(book)
 }
 
-// GetBook handles GET /api/v1/books/:id
+// GetBook handles GET /api/v1/books/:id. With include_availability=true the
+// book's stock at each warehouse is included. The response carries the book's
+// ETag, and a matching If-None-Match gets 304 Not Modified.
 func (h *BookHandler) GetBook(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -60,7 +70,20 @@ func (h *BookHandler) GetBook(c *fiber.Ctx) error {
 		})
 	}
 
+	includeAvailability := false
+	if value := c.Query("include_availability"); value != "" {
+		includeAvailability, err = strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid include_availability",
+			})
+		}
+	}
+
 	book, err := h.bookService.GetBook(c.Context(), id)
+	if err == nil && includeAvailability {
+		book.Availability, err = h.bookService.GetBookAvailability(c.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, service.ErrBookNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -72,57 +95,108 @@ func (h *BookHandler) GetBook(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, bookETag(book))
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.JSON(book)
 }
 
-// ListBooks handles GET /api/v1/books
-func (h *BookHandler) ListBooks(c *fiber.Ctx) error {
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
-
-	// Parse filters
-	filters := make(map[string]interface{})
-	if categoryID := c.Query("category_id"); categoryID != "" {
-		if id, err := uuid.Parse(categoryID); err == nil {
-			filters["category_id"] = id
+// GetBookByISBN handles GET /api/v1/books/isbn/:isbn. The ISBN may be an
+// ISBN-10 or ISBN-13, with or without hyphens.
+func (h *BookHandler) GetBookByISBN(c *fiber.Ctx) error {
+	book, err := h.bookService.GetBookByISBN(c.Context(), c.Params("isbn"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		}
-	}
-	if authorID := c.Query("author_id"); authorID != "" {
-		if id, err := uuid.Parse(authorID); err == nil {
-			filters["author_id"] = id
+		if errors.Is(err, service.ErrBookNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Book not found",
+			})
 		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get book",
+		})
 	}
-	if title := c.Query("title"); title != "" {
-		filters["title"] = title
+
+	c.Set(fiber.HeaderETag, bookETag(book))
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
 	}
-	if minPrice := c.Query("min_price"); minPrice != "" {
-		if price, err := strconv.ParseFloat(minPrice, 64); err == nil {
-			filters["min_price"] = price
-		}
+
+	return c.JSON(book)
+}
+
+// ListBooks handles GET /api/v1/books
+func (h *BookHandler) ListBooks(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
-	if maxPrice := c.Query("max_price"); maxPrice != "" {
-		if price, err := strconv.ParseFloat(maxPrice, 64); err == nil {
-			filters["max_price"] = price
-		}
+
+	filters, err := parseBookFilters(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
-	books, total, err := h.bookService.ListBooks(c.Context(), limit, offset, filters)
+	result, err := h.bookService.ListBooks(c.Context(), page, filters)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidSort) || errors.Is(err, service.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid filters",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to list books",
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"data":   books,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	response := bookPageResponse(result, page)
+
+	// Facet counts are opt-in: ?facets=category,format or ?facets=all
+	if facetsParam := c.Query("facets"); facetsParam != "" {
+		priceBounds, ok := parsePriceBounds(c.Query("price_buckets"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid price_buckets",
+			})
+		}
+
+		facets, err := h.bookService.GetBookFacets(c.Context(), filters, strings.Split(facetsParam, ","), priceBounds)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidInput) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid price_buckets",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to compute facets",
+			})
+		}
+		response["facets"] = facets
+	}
+
+	return c.JSON(response)
 }
 
-// UpdateBook handles PUT /api/v1/books/:id
+// UpdateBook handles PUT /api/v1/books/:id. The update only applies to the
+// version named by If-Match, or else by the body's version; a stale If-Match
+// gets 412 Precondition Failed and a stale body version 409 Conflict. A
+// stock_quantity other than the current stock gets 400 Bad Request. A missing
+// reorder_threshold keeps the stored one.
 func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -132,31 +206,84 @@ func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
 		})
 	}
 
-	var book domain.Book
-	if err := c.BodyParser(&book); err != nil {
+	// The outer stock_quantity tells a missing stock from a zero one, and the
+	// outer reorder_threshold a missing threshold, which is kept, from a null
+	// one, which clears it
+	var body struct {
+		domain.Book
+		StockQuantity    *int            `json:"stock_quantity"`
+		ReorderThreshold json.RawMessage `json:"reorder_threshold"`
+	}
+	if err := c.BodyParser(&body); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
+	book := body.Book
 	book.ID = id
-
-	if err := h.bookService.UpdateBook(c.Context(), &book); err != nil {
-		if errors.Is(err, service.ErrBookNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Book not found",
+	thresholdSet := body.ReorderThreshold != nil
+	if thresholdSet {
+		if err := json.Unmarshal(body.ReorderThreshold, &book.ReorderThreshold); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "reorder_threshold must be a whole number or null",
 			})
 		}
-		if errors.Is(err, service.ErrBookAlreadyExists) {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": err.Error(),
+	}
+
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch != "" {
+		version, ok := parseIfMatch(ifMatch)
+		if !ok {
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error": "If-Match must be * or a single ETag from GET",
 			})
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update book",
+		book.Version = version
+	}
+
+	if err := h.bookService.UpdateBook(c.Context(), &book, body.StockQuantity, thresholdSet); err != nil {
+		return bookUpdateError(c, err, ifMatch != "", "Failed to update book")
+	}
+
+	c.Set(fiber.HeaderETag, bookETag(&book))
+	return c.JSON(book)
+}
+
+// PatchBook handles PATCH /api/v1/books/:id with a JSON merge patch
+func (h *BookHandler) PatchBook(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid book ID",
+		})
+	}
+
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	if !strings.EqualFold(strings.TrimSpace(mediaType), mergePatchMediaType) {
+		c.Set("Accept-Patch", mergePatchMediaType)
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Content-Type must be " + mergePatchMediaType,
 		})
 	}
 
+	version := 0
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch != "" {
+		var ok bool
+		if version, ok = parseIfMatch(ifMatch); !ok {
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error": "If-Match must be * or a single ETag from GET",
+			})
+		}
+	}
+
+	book, err := h.bookService.PatchBook(c.Context(), id, version, c.Body())
+	if err != nil {
+		return bookUpdateError(c, err, ifMatch != "", "Failed to patch book")
+	}
+
+	c.Set(fiber.HeaderETag, bookETag(book))
 	return c.JSON(book)
 }
 
@@ -195,7 +322,10 @@ func (h *BookHandler) UpdateStock(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Quantity int `json:"quantity"`
+		WarehouseID *uuid.UUID `json:"warehouse_id"`
+		Quantity    int        `json:"quantity"`
+		Reason      string     `json:"reason"`
+		ReferenceID string     `json:"reference_id"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -203,18 +333,231 @@ func (h *BookHandler) UpdateStock(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.bookService.UpdateBookStock(c.Context(), id, req.Quantity); err != nil {
+	movement := &domain.StockMovement{
+		BookID:      id,
+		WarehouseID: req.WarehouseID,
+		Delta:       req.Quantity,
+		Reason:      req.Reason,
+		ReferenceID: req.ReferenceID,
+	}
+	if userID, ok := c.Locals("userID").(uuid.UUID); ok {
+		movement.ActorID = &userID
+	}
+
+	if err := h.bookService.UpdateBookStock(c.Context(), movement); err != nil {
+		if errors.Is(err, service.ErrInvalidInput) || errors.Is(err, service.ErrInvalidReason) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		if errors.Is(err, service.ErrBookNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Book not found",
 			})
 		}
+		if errors.Is(err, service.ErrWarehouseNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Warehouse not found",
+			})
+		}
+		if errors.Is(err, service.ErrInsufficientStock) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Insufficient stock",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update stock",
 		})
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Stock updated successfully",
+		"message":  "Stock updated successfully",
+		"movement": movement,
+	})
+}
+
+// parseBookFilters extracts the supported book list filters and sort from the
+// query string. Malformed values are reported rather than ignored.
+func parseBookFilters(c *fiber.Ctx) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+	for _, key := range []string{"category_id", "author_id", "publisher_id"} {
+		if value := c.Query(key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", key)
+			}
+			filters[key] = id
+		}
+	}
+	if format := strings.ToLower(strings.TrimSpace(c.Query("format"))); format != "" {
+		filters["format"] = format
+	}
+	if language := strings.TrimSpace(c.Query("language")); language != "" {
+		filters["language"] = language
+	}
+	if isbnParam := c.Query("isbn"); isbnParam != "" {
+		var isbns []string
+		for _, isbn := range strings.Split(isbnParam, ",") {
+			if isbn = strings.TrimSpace(isbn); isbn != "" {
+				isbns = append(isbns, isbn)
+			}
+		}
+		if len(isbns) == 0 {
+			return nil, errors.New("invalid isbn")
+		}
+		filters["isbn"] = isbns
+	}
+	if title := c.Query("title"); title != "" {
+		filters["title"] = title
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		filters["q"] = q
+	}
+	for _, key := range []string{"min_price", "max_price"} {
+		if value := c.Query(key); value != "" {
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price < 0 {
+				return nil, fmt.Errorf("invalid %s", key)
+			}
+			filters[key] = price
+		}
+	}
+	for _, key := range []string{"published_from", "published_to"} {
+		if value := c.Query(key); value != "" {
+			date, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: expected YYYY-MM-DD", key)
+			}
+			filters[key] = date
+		}
+	}
+	if inStock := c.Query("in_stock"); inStock != "" {
+		value, err := strconv.ParseBool(inStock)
+		if err != nil {
+			return nil, errors.New("invalid in_stock")
+		}
+		filters["in_stock"] = value
+	}
+
+	sort, err := service.ParseBookSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		return nil, err
+	}
+	if sort != nil {
+		filters["sort"] = *sort
+	}
+
+	return filters, nil
+}
+
+// parsePageRequest reads offset or cursor pagination from the query string.
+// The total is counted by default only for offset pages, since counting is
+// what makes deep pages slow.
+func parsePageRequest(c *fiber.Ctx) (domain.PageRequest, error) {
+	page := domain.PageRequest{Cursor: c.Query("cursor")}
+	page.Limit, _ = strconv.Atoi(c.Query("limit", "20"))
+	page.Offset, _ = strconv.Atoi(c.Query("offset", "0"))
+	page.IncludeTotal = page.Cursor == ""
+
+	if includeTotal := c.Query("include_total"); includeTotal != "" {
+		value, err := strconv.ParseBool(includeTotal)
+		if err != nil {
+			return page, errors.New("invalid include_total")
+		}
+		page.IncludeTotal = value
+	}
+	return page, nil
+}
+
+// bookPageResponse renders a page of books in the standard list envelope
+func bookPageResponse(page *domain.BookPage, req domain.PageRequest) fiber.Map {
+	response := fiber.Map{
+		"data":  page.Books,
+		"limit": page.Limit,
+	}
+	if req.Cursor == "" {
+		response["offset"] = page.Offset
+	}
+	if page.Total != nil {
+		response["total"] = *page.Total
+	}
+	if page.NextCursor != "" {
+		response["next_cursor"] = page.NextCursor
+	}
+	if page.PrevCursor != "" {
+		response["prev_cursor"] = page.PrevCursor
+	}
+	return response
+}
+
+// bookUpdateError maps errors from updating a book to HTTP responses. A stale
+// version is a failed precondition when the client sent If-Match.
+func bookUpdateError(c *fiber.Ctx, err error, ifMatch bool, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrVersionConflict):
+		status := fiber.StatusConflict
+		if ifMatch {
+			status = fiber.StatusPreconditionFailed
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrBookNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Book not found",
+		})
+	case errors.Is(err, service.ErrBookAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
 	})
 }
+
+// bookETag is the strong entity tag of a book's current version and stock.
+// Stock moves without bumping the version, so it is part of the tag for
+// If-None-Match, but If-Match only compares the version.
+func bookETag(book *domain.Book) string {
+	return `"` + strconv.Itoa(book.Version) + "-" + strconv.Itoa(book.StockQuantity) + `"`
+}
+
+// parseIfMatch returns the version named by an If-Match header, or 0 for "*",
+// which matches any version. A bare version such as "3" is accepted too. Weak
+// tags never match, as If-Match compares strongly.
+func parseIfMatch(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return 0, true
+	}
+	if len(value) < 3 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, false
+	}
+	tag, _, _ := strings.Cut(value[1:len(value)-1], "-")
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// parsePriceBounds parses a comma-separated list of price bucket upper bounds
+func parsePriceBounds(value string) ([]float64, bool) {
+	if value == "" {
+		return nil, true
+	}
+	var bounds []float64
+	for _, part := range strings.Split(value, ",") {
+		bound, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
+		}
+		bounds = append(bounds, bound)
+	}
+	return bounds, true
+}
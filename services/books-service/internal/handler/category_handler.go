@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// CategoryHandler handles HTTP requests for categories
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// CreateCategory handles POST /api/v1/categories
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var category domain.Category
+	if err := c.BodyParser(&category); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.categoryService.CreateCategory(c.Context(), &category); err != nil {
+		return categoryError(c, err, "Failed to create category")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(category)
+}
+
+// ListCategories handles GET /api/v1/categories
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	categories, err := h.categoryService.ListCategories(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list categories",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  categories,
+		"total": len(categories),
+	})
+}
+
+// GetCategoryTree handles GET /api/v1/categories/tree
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.categoryService.GetCategoryTree(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build category tree",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": tree,
+	})
+}
+
+// GetCategory handles GET /api/v1/categories/:id
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	category, err := h.categoryService.GetCategory(c.Context(), id)
+	if err != nil {
+		return categoryError(c, err, "Failed to get category")
+	}
+
+	return c.JSON(category)
+}
+
+// GetCategoryBySlug handles GET /api/v1/categories/slug/:slug
+func (h *CategoryHandler) GetCategoryBySlug(c *fiber.Ctx) error {
+	category, err := h.categoryService.GetCategoryBySlug(c.Context(), c.Params("slug"))
+	if err != nil {
+		return categoryError(c, err, "Failed to get category")
+	}
+
+	return c.JSON(category)
+}
+
+// UpdateCategory handles PUT /api/v1/categories/:id
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	// The outer reorder_threshold tells a missing threshold, which is kept,
+	// from a null one, which clears it
+	var body struct {
+		domain.Category
+		ReorderThreshold json.RawMessage `json:"reorder_threshold"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	category := body.Category
+	category.ID = id
+	thresholdSet := body.ReorderThreshold != nil
+	if thresholdSet {
+		if err := json.Unmarshal(body.ReorderThreshold, &category.ReorderThreshold); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "reorder_threshold must be a whole number or null",
+			})
+		}
+	}
+
+	if err := h.categoryService.UpdateCategory(c.Context(), &category, thresholdSet); err != nil {
+		return categoryError(c, err, "Failed to update category")
+	}
+
+	return c.JSON(category)
+}
+
+// DeleteCategory handles DELETE /api/v1/categories/:id
+// Pass ?cascade=true to also delete child categories and unlink their books.
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	cascade := c.QueryBool("cascade", false)
+
+	if err := h.categoryService.DeleteCategory(c.Context(), id, cascade); err != nil {
+		return categoryError(c, err, "Failed to delete category")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// categoryError maps category service errors to HTTP responses
+func categoryError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	case errors.Is(err, service.ErrCategoryAlreadyExists),
+		errors.Is(err, service.ErrCategoryHasChildren),
+		errors.Is(err, service.ErrCategoryHasBooks):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput),
+		errors.Is(err, service.ErrInvalidParent):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
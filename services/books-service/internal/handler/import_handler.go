@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// importMediaTypes maps request content types to catalog import formats
+var importMediaTypes = map[string]string{
+	"text/csv":             domain.ImportFormatCSV,
+	"application/x-ndjson": domain.ImportFormatNDJSON,
+	"application/jsonl":    domain.ImportFormatNDJSON,
+	"application/xml":      domain.ImportFormatONIX,
+	"text/xml":             domain.ImportFormatONIX,
+}
+
+// ImportHandler handles HTTP requests for bulk catalog imports
+type ImportHandler struct {
+	importService service.ImportService
+	maxSize       int
+	timeout       time.Duration
+}
+
+// NewImportHandler creates a new instance of ImportHandler. Uploads may be up
+// to maxSize bytes and take up to timeout to read and import.
+func NewImportHandler(importService service.ImportService, maxSize int, timeout time.Duration) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		maxSize:       maxSize,
+		timeout:       timeout,
+	}
+}
+
+// ImportBooks handles POST /api/v1/books/import. The format comes from
+// ?format=csv|ndjson|onix or else the Content-Type, and ?dry_run=true validates
+// the rows without saving them. Rows that fail are listed in the report. The
+// body is imported as it is read rather than buffered first.
+func (h *ImportHandler) ImportBooks(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+		format = importMediaTypes[strings.ToLower(strings.TrimSpace(mediaType))]
+	}
+	if format == "" {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Send text/csv, application/x-ndjson or application/xml, or set format",
+		})
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid dry_run",
+			})
+		}
+	}
+
+	if c.Request().Header.ContentLength() > h.maxSize {
+		return importTooLarge(c, h.maxSize, nil)
+	}
+
+	// Reading the body takes as long as the import, so it gets its own deadline
+	if conn := c.Context().Conn(); conn != nil {
+		if err := conn.SetReadDeadline(time.Now().Add(h.timeout)); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to import books",
+			})
+		}
+	}
+
+	var body io.Reader
+	if c.Request().IsBodyStream() {
+		body = c.Context().RequestBodyStream()
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+
+	report, err := h.importService.ImportBooks(c.Context(), format, &importBody{r: body, remaining: h.maxSize}, dryRun)
+	if err != nil {
+		if errors.Is(err, errImportTooLarge) {
+			return importTooLarge(c, h.maxSize, report)
+		}
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":  err.Error(),
+				"report": report,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":  "Failed to import books",
+			"report": report,
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// errImportTooLarge stops an import whose upload runs past the size limit
+var errImportTooLarge = errors.New("import is too large")
+
+// importBody fails once more than remaining bytes have been read, so an upload
+// without a Content-Length can't run past the limit and isn't cut short
+// silently either
+type importBody struct {
+	r         io.Reader
+	remaining int
+}
+
+func (b *importBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, errImportTooLarge
+	}
+	if len(p) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.r.Read(p)
+	b.remaining -= n
+	if b.remaining < 0 {
+		return 0, errImportTooLarge
+	}
+	return n, err
+}
+
+// importTooLarge rejects an upload over the limit, with the report of the
+// rows imported before it ran over
+func importTooLarge(c *fiber.Ctx, maxSize int, report *domain.ImportReport) error {
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"error":  fmt.Sprintf("Imports are limited to %d MB; load larger files with the import command", maxSize>>20),
+		"report": report,
+	})
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// InventoryHandler handles HTTP requests for the stock ledger
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+}
+
+// NewInventoryHandler creates a new instance of InventoryHandler
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// ListMovements handles GET /api/v1/books/:id/stock/movements
+func (h *InventoryHandler) ListMovements(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid book ID",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	movements, total, err := h.inventoryService.ListMovements(c.Context(), id, limit, offset)
+	if err != nil {
+		return inventoryError(c, err, "Failed to list stock movements")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   movements,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Reconcile handles GET /api/v1/books/:id/stock/reconciliation
+func (h *InventoryHandler) Reconcile(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid book ID",
+		})
+	}
+
+	reconciliation, err := h.inventoryService.Reconcile(c.Context(), id)
+	if err != nil {
+		return inventoryError(c, err, "Failed to reconcile stock")
+	}
+
+	return c.JSON(reconciliation)
+}
+
+// ListDiscrepancies handles GET /api/v1/stock/discrepancies
+func (h *InventoryHandler) ListDiscrepancies(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	discrepancies, err := h.inventoryService.ListDiscrepancies(c.Context(), limit)
+	if err != nil {
+		return inventoryError(c, err, "Failed to list stock discrepancies")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": discrepancies,
+	})
+}
+
+// ListLowStock handles GET /api/v1/inventory/low-stock
+func (h *InventoryHandler) ListLowStock(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	items, total, err := h.inventoryService.ListLowStock(c.Context(), limit, offset)
+	if err != nil {
+		return inventoryError(c, err, "Failed to list low stock")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// inventoryError maps inventory service errors to HTTP responses
+func inventoryError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, service.ErrBookNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Book not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// PublisherHandler handles HTTP requests for publishers
+type PublisherHandler struct {
+	publisherService service.PublisherService
+}
+
+// NewPublisherHandler creates a new instance of PublisherHandler
+func NewPublisherHandler(publisherService service.PublisherService) *PublisherHandler {
+	return &PublisherHandler{
+		publisherService: publisherService,
+	}
+}
+
+// CreatePublisher handles POST /api/v1/publishers
+func (h *PublisherHandler) CreatePublisher(c *fiber.Ctx) error {
+	var publisher domain.Publisher
+	if err := c.BodyParser(&publisher); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.publisherService.CreatePublisher(c.Context(), &publisher); err != nil {
+		return publisherError(c, err, "Failed to create publisher")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(publisher)
+}
+
+// ListPublishers handles GET /api/v1/publishers
+func (h *PublisherHandler) ListPublishers(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	publishers, total, err := h.publisherService.ListPublishers(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list publishers",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   publishers,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetPublisher handles GET /api/v1/publishers/:id
+func (h *PublisherHandler) GetPublisher(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid publisher ID",
+		})
+	}
+
+	publisher, err := h.publisherService.GetPublisher(c.Context(), id)
+	if err != nil {
+		return publisherError(c, err, "Failed to get publisher")
+	}
+
+	return c.JSON(publisher)
+}
+
+// UpdatePublisher handles PUT /api/v1/publishers/:id
+func (h *PublisherHandler) UpdatePublisher(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid publisher ID",
+		})
+	}
+
+	var publisher domain.Publisher
+	if err := c.BodyParser(&publisher); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	publisher.ID = id
+
+	if err := h.publisherService.UpdatePublisher(c.Context(), &publisher); err != nil {
+		return publisherError(c, err, "Failed to update publisher")
+	}
+
+	return c.JSON(publisher)
+}
+
+// DeletePublisher handles DELETE /api/v1/publishers/:id
+func (h *PublisherHandler) DeletePublisher(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid publisher ID",
+		})
+	}
+
+	if err := h.publisherService.DeletePublisher(c.Context(), id); err != nil {
+		return publisherError(c, err, "Failed to delete publisher")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListPublisherBooks handles GET /api/v1/publishers/:id/books
+// Accepts the same filters as GET /api/v1/books.
+func (h *PublisherHandler) ListPublisherBooks(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid publisher ID",
+		})
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filters, err := parseBookFilters(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.publisherService.ListPublisherBooks(c.Context(), id, page, filters)
+	if err != nil {
+		return publisherError(c, err, "Failed to list publisher books")
+	}
+
+	return c.JSON(bookPageResponse(result, page))
+}
+
+// publisherError maps publisher service errors to HTTP responses
+func publisherError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrPublisherNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Publisher not found",
+		})
+	case errors.Is(err, service.ErrPublisherHasBooks):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrInvalidCursor):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// ReservationHandler handles HTTP requests for stock reservations
+type ReservationHandler struct {
+	reservationService service.ReservationService
+}
+
+// NewReservationHandler creates a new instance of ReservationHandler
+func NewReservationHandler(reservationService service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+type createReservationRequest struct {
+	BookID      uuid.UUID `json:"book_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+	TTLSeconds  int       `json:"ttl_seconds"`
+}
+
+// CreateReservation handles POST /api/v1/reservations
+func (h *ReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	var req createReservationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+
+	reservation, err := h.reservationService.CreateReservation(c.Context(), userID, req.BookID, req.WarehouseID, req.Quantity, ttl)
+	if err != nil {
+		return reservationError(c, err, "Failed to create reservation")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reservation)
+}
+
+// GetReservation handles GET /api/v1/reservations/:id
+func (h *ReservationHandler) GetReservation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid reservation ID",
+		})
+	}
+
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	reservation, err := h.reservationService.GetReservation(c.Context(), userID, id)
+	if err != nil {
+		return reservationError(c, err, "Failed to get reservation")
+	}
+
+	return c.JSON(reservation)
+}
+
+// ConfirmReservation handles POST /api/v1/reservations/:id/confirm
+func (h *ReservationHandler) ConfirmReservation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid reservation ID",
+		})
+	}
+
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	reservation, err := h.reservationService.ConfirmReservation(c.Context(), userID, id)
+	if err != nil {
+		return reservationError(c, err, "Failed to confirm reservation")
+	}
+
+	return c.JSON(reservation)
+}
+
+// ReleaseReservation handles POST /api/v1/reservations/:id/release
+func (h *ReservationHandler) ReleaseReservation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid reservation ID",
+		})
+	}
+
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	reservation, err := h.reservationService.ReleaseReservation(c.Context(), userID, id)
+	if err != nil {
+		return reservationError(c, err, "Failed to release reservation")
+	}
+
+	return c.JSON(reservation)
+}
+
+// reservationError maps reservation service errors to HTTP responses
+func reservationError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrReservationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Reservation not found",
+		})
+	case errors.Is(err, service.ErrBookNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Book not found",
+		})
+	case errors.Is(err, service.ErrWarehouseNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Warehouse not found",
+		})
+	case errors.Is(err, service.ErrInsufficientStock),
+		errors.Is(err, service.ErrReservationNotHeld),
+		errors.Is(err, service.ErrTooManyReservations):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
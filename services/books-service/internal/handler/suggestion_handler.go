@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// SuggestionHandler handles HTTP requests for search autocomplete
+type SuggestionHandler struct {
+	suggestionService service.SuggestionService
+}
+
+// NewSuggestionHandler creates a new instance of SuggestionHandler
+func NewSuggestionHandler(suggestionService service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+// Suggest handles GET /api/v1/books/suggest
+func (h *SuggestionHandler) Suggest(c *fiber.Ctx) error {
+	suggestions, err := h.suggestionService.Suggest(c.Context(), c.Query("q"), c.QueryInt("limit", 0))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Query is too long",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get suggestions",
+		})
+	}
+
+	// Let browsers reuse results while the user edits the same prefix
+	c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+	return c.JSON(suggestions)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/service"
+)
+
+// WarehouseHandler handles HTTP requests for warehouses
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+}
+
+// NewWarehouseHandler creates a new instance of WarehouseHandler
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+	}
+}
+
+// CreateWarehouse handles POST /api/v1/warehouses
+func (h *WarehouseHandler) CreateWarehouse(c *fiber.Ctx) error {
+	var warehouse domain.Warehouse
+	if err := c.BodyParser(&warehouse); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.warehouseService.CreateWarehouse(c.Context(), &warehouse); err != nil {
+		return warehouseError(c, err, "Failed to create warehouse")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(warehouse)
+}
+
+// ListWarehouses handles GET /api/v1/warehouses
+func (h *WarehouseHandler) ListWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.warehouseService.ListWarehouses(c.Context())
+	if err != nil {
+		return warehouseError(c, err, "Failed to list warehouses")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": warehouses,
+	})
+}
+
+// GetWarehouse handles GET /api/v1/warehouses/:id
+func (h *WarehouseHandler) GetWarehouse(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid warehouse ID",
+		})
+	}
+
+	warehouse, err := h.warehouseService.GetWarehouse(c.Context(), id)
+	if err != nil {
+		return warehouseError(c, err, "Failed to get warehouse")
+	}
+
+	return c.JSON(warehouse)
+}
+
+// UpdateWarehouse handles PUT /api/v1/warehouses/:id
+func (h *WarehouseHandler) UpdateWarehouse(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid warehouse ID",
+		})
+	}
+
+	var warehouse domain.Warehouse
+	if err := c.BodyParser(&warehouse); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	warehouse.ID = id
+
+	if err := h.warehouseService.UpdateWarehouse(c.Context(), &warehouse); err != nil {
+		return warehouseError(c, err, "Failed to update warehouse")
+	}
+
+	return c.JSON(warehouse)
+}
+
+// warehouseError maps warehouse service errors to HTTP responses
+func warehouseError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrWarehouseNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Warehouse not found",
+		})
+	case errors.Is(err, service.ErrWarehouseAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	customJWT "github.com/youngermaster/bookstore/services/books-service/pkg/jwt"
+)
+
+// AuthMiddleware creates a middleware that validates JWT tokens issued by the users service
+func AuthMiddleware(verifier *customJWT.Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "No authorization header",
+			})
+		}
+
+		// Extract token from "Bearer <token>"
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid authorization format",
+			})
+		}
+
+		// Validate token
+		claims, err := verifier.ValidateToken(tokenString)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired token",
+			})
+		}
+
+		// Store user info in context
+		c.Locals("userID", claims.UserID)
+		c.Locals("userEmail", claims.Email)
+		c.Locals("userRoles", claims.Roles)
+		c.Locals("userPermissions", claims.Permissions)
+
+		return c.Next()
+	}
+}
+
+// RequirePermission creates a middleware that checks if the user holds any of the given permissions
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userPermissions, ok := c.Locals("userPermissions").([]string)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied",
+			})
+		}
+
+		for _, required := range permissions {
+			for _, granted := range userPermissions {
+				if granted == required {
+					return c.Next()
+				}
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit creates a middleware that rejects request bodies over limit bytes.
+// The server streams bodies over its own limit to the handlers, so without
+// this c.Body() would read a body of any size. Requests skip returns true for
+// read their bodies as streams and limit them themselves.
+func BodyLimit(limit int, skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		length := req.Header.ContentLength()
+		if skip != nil && skip(c) {
+			// A handler that stops early leaves the rest of a streamed body
+			// unread on the connection
+			if length < 0 || length > limit {
+				c.Context().SetConnectionClose()
+			}
+			return c.Next()
+		}
+
+		if !req.IsBodyStream() || length >= 0 && length <= limit {
+			return c.Next()
+		}
+		if length > limit {
+			return tooLarge(c)
+		}
+
+		// A chunked body has no length up front, so read it up to the limit
+		body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read request body",
+			})
+		}
+		if len(body) > limit {
+			return tooLarge(c)
+		}
+		req.SetBody(body)
+		return c.Next()
+	}
+}
+
+// tooLarge rejects the request and closes the connection, since the rest of
+// the body is still unread on it
+func tooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"error": "Request body is too large",
+	})
+}
@@ -10,7 +10,8 @@ func CORS() fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000,http://localhost:8080",
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,If-Match,If-None-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: true,
 	})
 }
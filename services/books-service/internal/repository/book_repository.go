@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
@@ -12,10 +14,75 @@ type BookRepository interface {
 	Create(ctx context.Context, book *domain.Book) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Book, error)
 	FindByISBN(ctx context.Context, isbn string) (*domain.Book, error)
-	FindAll(ctx context.Context, limit, offset int, filters map[string]interface{}) ([]domain.Book, int64, error)
-	Update(ctx context.Context, book *domain.Book) error
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Book, error)
+	FindAll(ctx context.Context, limit, offset int, keyset *domain.Keyset, filters map[string]interface{}) ([]domain.Book, error)
+	Count(ctx context.Context, filters map[string]interface{}) (int64, error)
+	Facets(ctx context.Context, filters map[string]interface{}, facets []string, priceBounds []float64) (*domain.Facets, error)
+	// Update applies only if the stored version matches book.Version
+	Update(ctx context.Context, book *domain.Book) (bool, error)
+	// Patch is Update for the columns and links in patch only
+	Patch(ctx context.Context, id uuid.UUID, version int, patch *domain.BookPatch) (bool, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error
+	UpdateStock(ctx context.Context, movement *domain.StockMovement) (bool, error)
+}
+
+// StockMovementRepository defines read access to the stock ledger. Movements
+// are written by BookRepository and ReservationRepository in the same
+// transaction as the stock change they record.
+type StockMovementRepository interface {
+	FindByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]domain.StockMovement, int64, error)
+	Reconcile(ctx context.Context, bookID uuid.UUID) (*domain.StockReconciliation, error)
+	FindDiscrepancies(ctx context.Context, limit int) ([]domain.StockReconciliation, error)
+}
+
+// StockAlertRepository defines the interface for low-stock detection. A book is
+// low when its stock is at or below its reorder threshold, or else the highest
+// threshold among its categories.
+type StockAlertRepository interface {
+	FindLowStock(ctx context.Context, limit, offset int) ([]domain.LowStockItem, int64, error)
+	// Open records an alert for every low book without an open one and
+	// returns how many were opened
+	Open(ctx context.Context) (int64, error)
+	// ResolveRecovered closes the alerts of books that are no longer low
+	ResolveRecovered(ctx context.Context) (int64, error)
+	FindUnnotified(ctx context.Context, limit int) ([]domain.StockAlert, error)
+	MarkNotified(ctx context.Context, id uuid.UUID) error
+}
+
+// WarehouseRepository defines the interface for warehouse data access. Stock
+// levels are changed by BookRepository and ReservationRepository.
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	FindByCode(ctx context.Context, code string) (*domain.Warehouse, error)
+	FindAll(ctx context.Context) ([]domain.Warehouse, error)
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	FindStockByBookID(ctx context.Context, bookID uuid.UUID) ([]domain.WarehouseStock, error)
+}
+
+// ErrTooManyHolds is returned by Hold when the user already has as many
+// unexpired holds as allowed
+var ErrTooManyHolds = errors.New("too many active reservations")
+
+// ReservationRepository defines the interface for stock reservations. Every
+// method that moves stock does so in the same statement or transaction as the
+// status change, so concurrent callers can't oversell or double-release.
+type ReservationRepository interface {
+	// Hold takes the quantity out of the stock at the reservation's
+	// warehouse, or at the warehouse with the most stock when none is set, and
+	// stores the reservation; false means there wasn't enough stock. It fails
+	// with ErrTooManyHolds if the user already has maxHeld unexpired holds.
+	Hold(ctx context.Context, reservation *domain.Reservation, maxHeld int) (bool, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error)
+	// Confirm marks an unexpired held reservation confirmed; false means it
+	// was no longer held
+	Confirm(ctx context.Context, reservation *domain.Reservation) (bool, error)
+	// Release returns a held reservation's quantity to stock; false means it
+	// was no longer held
+	Release(ctx context.Context, reservation *domain.Reservation) (bool, error)
+	// ReleaseExpired returns the stock of up to limit holds that expired
+	// before now and reports the books whose stock changed
+	ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
 }
 
 // CategoryRepository defines the interface for category data access
@@ -23,25 +90,52 @@ type CategoryRepository interface {
 	Create(ctx context.Context, category *domain.Category) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 	FindBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error)
 	FindAll(ctx context.Context) ([]domain.Category, error)
 	Update(ctx context.Context, category *domain.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	FindDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	CountBooks(ctx context.Context, ids []uuid.UUID) (int64, error)
+	// FindBookIDs returns the books linked to any of the categories
+	FindBookIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	DeleteTree(ctx context.Context, ids []uuid.UUID) error
 }
 
 // AuthorRepository defines the interface for author data access
 type AuthorRepository interface {
 	Create(ctx context.Context, author *domain.Author) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Author, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Author, error)
+	// FindByName matches case-insensitively and returns the oldest match
+	FindByName(ctx context.Context, name string) (*domain.Author, error)
 	FindAll(ctx context.Context, limit, offset int) ([]domain.Author, int64, error)
 	Update(ctx context.Context, author *domain.Author) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	CountBooks(ctx context.Context, id uuid.UUID) (int64, error)
+	FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
 }
 
 // PublisherRepository defines the interface for publisher data access
 type PublisherRepository interface {
 	Create(ctx context.Context, publisher *domain.Publisher) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Publisher, error)
+	// FindByName matches case-insensitively and returns the oldest match
+	FindByName(ctx context.Context, name string) (*domain.Publisher, error)
 	FindAll(ctx context.Context, limit, offset int) ([]domain.Publisher, int64, error)
 	Update(ctx context.Context, publisher *domain.Publisher) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	CountBooks(ctx context.Context, id uuid.UUID) (int64, error)
+	FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+}
+
+// RecordReferenceRepository defines the interface for the record references
+// of imported feeds
+type RecordReferenceRepository interface {
+	FindByReference(ctx context.Context, reference string) (*domain.BookRecordReference, error)
+	Save(ctx context.Context, reference string, bookID uuid.UUID) error
+}
+
+// SuggestionRepository defines the interface for autocomplete lookups
+type SuggestionRepository interface {
+	Suggest(ctx context.Context, query string, limit int) ([]domain.Suggestion, error)
 }
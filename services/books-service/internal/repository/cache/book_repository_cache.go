@@ -0,0 +1,447 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+)
+
+const (
+	keyPrefix     = "books:"
+	listGenKey    = keyPrefix + "list:gen"
+	maxListOffset = 100
+)
+
+// listFilters are the FindAll filters worth caching. Free-text filters such as
+// title, and ISBN lists, have too many distinct values to be useful and are
+// always read through.
+var listFilters = map[string]bool{
+	"category_id":    true,
+	"author_id":      true,
+	"publisher_id":   true,
+	"format":         true,
+	"language":       true,
+	"min_price":      true,
+	"max_price":      true,
+	"published_from": true,
+	"published_to":   true,
+	"in_stock":       true,
+	"sort":           true,
+}
+
+// Stats holds cache counters since startup
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
+}
+
+// BookRepository is a read-through Redis cache in front of another BookRepository.
+// Redis failures are logged and counted, and the call falls through to the
+// wrapped repository so the service keeps working while Redis is down.
+type BookRepository struct {
+	next   repository.BookRepository
+	client *redis.Client
+	ttl    time.Duration
+	log    zerolog.Logger
+
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	failures atomic.Uint64
+}
+
+// NewBookRepository wraps next with a Redis cache whose entries expire after ttl
+func NewBookRepository(next repository.BookRepository, client *redis.Client, ttl time.Duration, log zerolog.Logger) *BookRepository {
+	return &BookRepository{
+		next:   next,
+		client: client,
+		ttl:    ttl,
+		log:    log,
+	}
+}
+
+// Stats returns a snapshot of the hit, miss and error counters
+func (r *BookRepository) Stats() Stats {
+	return Stats{
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+		Errors: r.failures.Load(),
+	}
+}
+
+func (r *BookRepository) Create(ctx context.Context, book *domain.Book) error {
+	if err := r.next.Create(ctx, book); err != nil {
+		return err
+	}
+	r.invalidateLists(ctx)
+	return nil
+}
+
+func (r *BookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Book, error) {
+	var book domain.Book
+	if r.get(ctx, bookKey(id), &book) {
+		return &book, nil
+	}
+
+	found, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, bookKey(id), found)
+	return found, nil
+}
+
+// FindByISBN caches the ISBN as a pointer to the book ID so that invalidating
+// the book entry is enough to keep ISBN lookups fresh
+func (r *BookRepository) FindByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	var id uuid.UUID
+	if r.get(ctx, isbnKey(isbn), &id) {
+		var book domain.Book
+		if r.get(ctx, bookKey(id), &book) && book.ISBN == isbn {
+			return &book, nil
+		}
+	}
+
+	found, err := r.next.FindByISBN(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, isbnKey(isbn), found.ID)
+	r.set(ctx, bookKey(found.ID), found)
+	return found, nil
+}
+
+// FindByIDs serves cached books with a single MGET and loads the rest from
+// the wrapped repository
+func (r *BookRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Book, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = bookKey(id)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.fail("mget books", err)
+		return r.next.FindByIDs(ctx, ids)
+	}
+
+	books := make([]domain.Book, 0, len(ids))
+	var missing []uuid.UUID
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			r.misses.Add(1)
+			missing = append(missing, ids[i])
+			continue
+		}
+		var book domain.Book
+		if err := json.Unmarshal([]byte(data), &book); err != nil {
+			r.fail("decode "+keys[i], err)
+			missing = append(missing, ids[i])
+			continue
+		}
+		r.hits.Add(1)
+		books = append(books, book)
+	}
+
+	if len(missing) == 0 {
+		return books, nil
+	}
+
+	found, err := r.next.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		r.set(ctx, bookKey(found[i].ID), &found[i])
+	}
+	return append(books, found...), nil
+}
+
+// FindAll caches offset pages near the start of a listing. Cursor pages are
+// read through since each cursor is rarely requested twice.
+func (r *BookRepository) FindAll(ctx context.Context, limit, offset int, keyset *domain.Keyset, filters map[string]interface{}) ([]domain.Book, error) {
+	if keyset != nil || !cacheableList(offset, filters) {
+		return r.next.FindAll(ctx, limit, offset, keyset, filters)
+	}
+
+	gen, ok := r.listGeneration(ctx)
+	if !ok {
+		return r.next.FindAll(ctx, limit, offset, keyset, filters)
+	}
+	key := listKey("list", gen, fmt.Sprintf("limit=%d&offset=%d", limit, offset), filters)
+
+	var books []domain.Book
+	if r.get(ctx, key, &books) {
+		return books, nil
+	}
+
+	books, err := r.next.FindAll(ctx, limit, offset, keyset, filters)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, key, books)
+	return books, nil
+}
+
+// Count is cached alongside listings and invalidated with them
+func (r *BookRepository) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	if !cacheableList(0, filters) {
+		return r.next.Count(ctx, filters)
+	}
+
+	gen, ok := r.listGeneration(ctx)
+	if !ok {
+		return r.next.Count(ctx, filters)
+	}
+	key := listKey("count", gen, "", filters)
+
+	var total int64
+	if r.get(ctx, key, &total) {
+		return total, nil
+	}
+
+	total, err := r.next.Count(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	r.set(ctx, key, total)
+	return total, nil
+}
+
+// Facets is cached alongside listings and invalidated with them
+func (r *BookRepository) Facets(ctx context.Context, filters map[string]interface{}, facets []string, priceBounds []float64) (*domain.Facets, error) {
+	if !cacheableList(0, filters) {
+		return r.next.Facets(ctx, filters, facets, priceBounds)
+	}
+
+	gen, ok := r.listGeneration(ctx)
+	if !ok {
+		return r.next.Facets(ctx, filters, facets, priceBounds)
+	}
+	key := listKey("facets", gen, fmt.Sprintf("facets=%v&price=%v", facets, priceBounds), filters)
+
+	var cached domain.Facets
+	if r.get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	result, err := r.next.Facets(ctx, filters, facets, priceBounds)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, key, result)
+	return result, nil
+}
+
+func (r *BookRepository) Update(ctx context.Context, book *domain.Book) (bool, error) {
+	applied, err := r.next.Update(ctx, book)
+	if err != nil || !applied {
+		return applied, err
+	}
+	r.invalidateBook(ctx, book.ID)
+	return true, nil
+}
+
+func (r *BookRepository) Patch(ctx context.Context, id uuid.UUID, version int, patch *domain.BookPatch) (bool, error) {
+	applied, err := r.next.Patch(ctx, id, version, patch)
+	if err != nil || !applied {
+		return applied, err
+	}
+	r.invalidateBook(ctx, id)
+	return true, nil
+}
+
+func (r *BookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidateBook(ctx, id)
+	return nil
+}
+
+func (r *BookRepository) UpdateStock(ctx context.Context, movement *domain.StockMovement) (bool, error) {
+	applied, err := r.next.UpdateStock(ctx, movement)
+	if err != nil || !applied {
+		return applied, err
+	}
+	r.invalidateBook(ctx, movement.BookID)
+	return true, nil
+}
+
+// get loads a cached value into dest and reports whether it was a hit
+func (r *BookRepository) get(ctx context.Context, key string, dest interface{}) bool {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			r.misses.Add(1)
+		} else {
+			r.fail("get "+key, err)
+		}
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		r.fail("decode "+key, err)
+		return false
+	}
+	r.hits.Add(1)
+	return true
+}
+
+func (r *BookRepository) set(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		r.fail("encode "+key, err)
+		return
+	}
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		r.fail("set "+key, err)
+	}
+}
+
+// listGeneration returns the current list generation; ok is false when Redis
+// can't be reached
+func (r *BookRepository) listGeneration(ctx context.Context) (string, bool) {
+	gen, err := r.client.Get(ctx, listGenKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", true
+	}
+	if err != nil {
+		r.fail("get list generation", err)
+		return "", false
+	}
+	return gen, true
+}
+
+// invalidateBook drops the cached book and every cached listing
+func (r *BookRepository) invalidateBook(ctx context.Context, id uuid.UUID) {
+	if err := r.client.Del(ctx, bookKey(id)).Err(); err != nil {
+		r.fail("delete "+bookKey(id), err)
+	}
+	r.invalidateLists(ctx)
+}
+
+// invalidateBooks drops the cached books and every cached listing
+func (r *BookRepository) invalidateBooks(ctx context.Context, ids []uuid.UUID) {
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = bookKey(id)
+		}
+		if err := r.client.Del(ctx, keys...).Err(); err != nil {
+			r.fail("delete books", err)
+		}
+	}
+	r.invalidateLists(ctx)
+}
+
+// invalidateLists bumps the list generation so older list entries are never
+// read again and simply expire
+func (r *BookRepository) invalidateLists(ctx context.Context) {
+	if err := r.client.Incr(ctx, listGenKey).Err(); err != nil {
+		r.fail("bump list generation", err)
+	}
+}
+
+func (r *BookRepository) fail(op string, err error) {
+	r.failures.Add(1)
+	r.log.Warn().Err(err).Str("op", op).Msg("Book cache unavailable, falling back to database")
+}
+
+func cacheableList(offset int, filters map[string]interface{}) bool {
+	if offset > maxListOffset {
+		return false
+	}
+	for key := range filters {
+		if !listFilters[key] {
+			return false
+		}
+	}
+	return true
+}
+
+func bookKey(id uuid.UUID) string {
+	return keyPrefix + "id:" + id.String()
+}
+
+func isbnKey(isbn string) string {
+	return keyPrefix + "isbn:" + isbn
+}
+
+// listKey builds a stable key from the list generation, the request
+// parameters and the filters
+func listKey(kind, gen, params string, filters map[string]interface{}) string {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(params)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "&%s=%v", key, filters[key])
+	}
+
+	sum := sha1.Sum([]byte(b.String()))
+	return keyPrefix + kind + ":" + gen + ":" + hex.EncodeToString(sum[:])
+}
+
+// ReservationRepository drops cached books whenever a reservation moves their
+// stock, so listings and lookups don't show copies that are already held
+type ReservationRepository struct {
+	next  repository.ReservationRepository
+	books *BookRepository
+}
+
+// NewReservationRepository wraps next so it invalidates entries in books
+func NewReservationRepository(next repository.ReservationRepository, books *BookRepository) *ReservationRepository {
+	return &ReservationRepository{next: next, books: books}
+}
+
+func (r *ReservationRepository) Hold(ctx context.Context, reservation *domain.Reservation, maxHeld int) (bool, error) {
+	held, err := r.next.Hold(ctx, reservation, maxHeld)
+	if err != nil || !held {
+		return held, err
+	}
+	r.books.invalidateBook(ctx, reservation.BookID)
+	return true, nil
+}
+
+func (r *ReservationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *ReservationRepository) Confirm(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	return r.next.Confirm(ctx, reservation)
+}
+
+func (r *ReservationRepository) Release(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	released, err := r.next.Release(ctx, reservation)
+	if err != nil || !released {
+		return released, err
+	}
+	r.books.invalidateBook(ctx, reservation.BookID)
+	return true, nil
+}
+
+func (r *ReservationRepository) ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	bookIDs, err := r.next.ReleaseExpired(ctx, now, limit)
+	for _, id := range bookIDs {
+		r.books.invalidateBook(ctx, id)
+	}
+	return bookIDs, err
+}
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+)
+
+// Cached books embed their authors, publisher and categories, so changing or
+// deleting one of those has to drop the books that link to it. The books are
+// looked up after an update, so newly linked books are included, and before a
+// delete, while the links still exist.
+
+// AuthorRepository drops cached books when an author they list changes
+type AuthorRepository struct {
+	next  repository.AuthorRepository
+	books *BookRepository
+}
+
+// NewAuthorRepository wraps next so it invalidates entries in books
+func NewAuthorRepository(next repository.AuthorRepository, books *BookRepository) *AuthorRepository {
+	return &AuthorRepository{next: next, books: books}
+}
+
+func (r *AuthorRepository) Create(ctx context.Context, author *domain.Author) error {
+	return r.next.Create(ctx, author)
+}
+
+func (r *AuthorRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Author, error) {
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *AuthorRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Author, error) {
+	return r.next.FindByIDs(ctx, ids)
+}
+
+func (r *AuthorRepository) FindByName(ctx context.Context, name string) (*domain.Author, error) {
+	return r.next.FindByName(ctx, name)
+}
+
+func (r *AuthorRepository) FindAll(ctx context.Context, limit, offset int) ([]domain.Author, int64, error) {
+	return r.next.FindAll(ctx, limit, offset)
+}
+
+func (r *AuthorRepository) Update(ctx context.Context, author *domain.Author) error {
+	if err := r.next.Update(ctx, author); err != nil {
+		return err
+	}
+	bookIDs, err := r.next.FindBookIDs(ctx, author.ID)
+	r.books.invalidateLinked(ctx, bookIDs, err)
+	return nil
+}
+
+func (r *AuthorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	bookIDs, findErr := r.next.FindBookIDs(ctx, id)
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.books.invalidateLinked(ctx, bookIDs, findErr)
+	return nil
+}
+
+func (r *AuthorRepository) CountBooks(ctx context.Context, id uuid.UUID) (int64, error) {
+	return r.next.CountBooks(ctx, id)
+}
+
+func (r *AuthorRepository) FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return r.next.FindBookIDs(ctx, id)
+}
+
+// PublisherRepository drops cached books when their publisher changes
+type PublisherRepository struct {
+	next  repository.PublisherRepository
+	books *BookRepository
+}
+
+// NewPublisherRepository wraps next so it invalidates entries in books
+func NewPublisherRepository(next repository.PublisherRepository, books *BookRepository) *PublisherRepository {
+	return &PublisherRepository{next: next, books: books}
+}
+
+func (r *PublisherRepository) Create(ctx context.Context, publisher *domain.Publisher) error {
+	return r.next.Create(ctx, publisher)
+}
+
+func (r *PublisherRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Publisher, error) {
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *PublisherRepository) FindByName(ctx context.Context, name string) (*domain.Publisher, error) {
+	return r.next.FindByName(ctx, name)
+}
+
+func (r *PublisherRepository) FindAll(ctx context.Context, limit, offset int) ([]domain.Publisher, int64, error) {
+	return r.next.FindAll(ctx, limit, offset)
+}
+
+func (r *PublisherRepository) Update(ctx context.Context, publisher *domain.Publisher) error {
+	if err := r.next.Update(ctx, publisher); err != nil {
+		return err
+	}
+	bookIDs, err := r.next.FindBookIDs(ctx, publisher.ID)
+	r.books.invalidateLinked(ctx, bookIDs, err)
+	return nil
+}
+
+func (r *PublisherRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	bookIDs, findErr := r.next.FindBookIDs(ctx, id)
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.books.invalidateLinked(ctx, bookIDs, findErr)
+	return nil
+}
+
+func (r *PublisherRepository) CountBooks(ctx context.Context, id uuid.UUID) (int64, error) {
+	return r.next.CountBooks(ctx, id)
+}
+
+func (r *PublisherRepository) FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return r.next.FindBookIDs(ctx, id)
+}
+
+// CategoryRepository drops cached books when a category they're in changes
+type CategoryRepository struct {
+	next  repository.CategoryRepository
+	books *BookRepository
+}
+
+// NewCategoryRepository wraps next so it invalidates entries in books
+func NewCategoryRepository(next repository.CategoryRepository, books *BookRepository) *CategoryRepository {
+	return &CategoryRepository{next: next, books: books}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return r.next.Create(ctx, category)
+}
+
+func (r *CategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *CategoryRepository) FindBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	return r.next.FindBySlug(ctx, slug)
+}
+
+func (r *CategoryRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error) {
+	return r.next.FindByIDs(ctx, ids)
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
+	return r.next.FindAll(ctx)
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	if err := r.next.Update(ctx, category); err != nil {
+		return err
+	}
+	bookIDs, err := r.next.FindBookIDs(ctx, []uuid.UUID{category.ID})
+	r.books.invalidateLinked(ctx, bookIDs, err)
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	bookIDs, findErr := r.next.FindBookIDs(ctx, []uuid.UUID{id})
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.books.invalidateLinked(ctx, bookIDs, findErr)
+	return nil
+}
+
+func (r *CategoryRepository) FindDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return r.next.FindDescendantIDs(ctx, id)
+}
+
+func (r *CategoryRepository) CountBooks(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	return r.next.CountBooks(ctx, ids)
+}
+
+func (r *CategoryRepository) FindBookIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	return r.next.FindBookIDs(ctx, ids)
+}
+
+func (r *CategoryRepository) DeleteTree(ctx context.Context, ids []uuid.UUID) error {
+	bookIDs, findErr := r.next.FindBookIDs(ctx, ids)
+	if err := r.next.DeleteTree(ctx, ids); err != nil {
+		return err
+	}
+	r.books.invalidateLinked(ctx, bookIDs, findErr)
+	return nil
+}
+
+// invalidateLinked drops the books a linked record was found on, and every
+// cached listing. When the books couldn't be found only the listings are
+// dropped, and the book entries stay stale until they expire.
+func (r *BookRepository) invalidateLinked(ctx context.Context, bookIDs []uuid.UUID, findErr error) {
+	if findErr != nil {
+		r.log.Warn().Err(findErr).Msg("Failed to find the cached books to invalidate")
+	}
+	r.invalidateBooks(ctx, bookIDs)
+}
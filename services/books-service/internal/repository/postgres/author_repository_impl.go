@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type authorRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorRepository creates a new instance of AuthorRepository
+func NewAuthorRepository(db *gorm.DB) repository.AuthorRepository {
+	return &authorRepository{db: db}
+}
+
+func (r *authorRepository) Create(ctx context.Context, author *domain.Author) error {
+	return r.db.WithContext(ctx).Create(author).Error
+}
+
+func (r *authorRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Author, error) {
+	var author domain.Author
+	err := r.db.WithContext(ctx).First(&author, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Author, error) {
+	var authors []domain.Author
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&authors).Error
+	return authors, err
+}
+
+func (r *authorRepository) FindByName(ctx context.Context, name string) (*domain.Author, error) {
+	var author domain.Author
+	err := r.db.WithContext(ctx).
+		Where("lower(name) = lower(?)", name).
+		Order("created_at").
+		First(&author).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) FindAll(ctx context.Context, limit, offset int) ([]domain.Author, int64, error) {
+	var authors []domain.Author
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Author{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Limit(limit).
+		Offset(offset).
+		Order("name ASC").
+		Find(&authors).Error
+
+	return authors, total, err
+}
+
+func (r *authorRepository) Update(ctx context.Context, author *domain.Author) error {
+	return r.db.WithContext(ctx).Save(author).Error
+}
+
+func (r *authorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.Author{}, "id = ?", id).Error
+}
+
+func (r *authorRepository) CountBooks(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.BookAuthor{}).
+		Where("author_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *authorRepository) FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&domain.BookAuthor{}).
+		Where("author_id = ?", id).
+		Pluck("book_id", &ids).Error
+	return ids, err
+}
@@ -3,11 +3,13 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
 	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type bookRepository struct {
@@ -19,8 +21,49 @@ func NewBookRepository(db *gorm.DB) repository.BookRepository {
 	return &bookRepository{db: db}
 }
 
+// Create inserts the book with its authors in the order given, puts its
+// initial stock in the default warehouse and opens its stock ledger with it
 func (r *bookRepository) Create(ctx context.Context, book *domain.Book) error {
-	return r.db.WithContext(ctx).Create(book).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The warehouse level brings stock_quantity up to the initial stock
+		initial := book.StockQuantity
+		book.StockQuantity = 0
+		if err := tx.Create(book).Error; err != nil {
+			return err
+		}
+		// The author links are created in the default order, so number them
+		for i, author := range book.Authors {
+			if i == 0 {
+				continue
+			}
+			err := tx.Model(&domain.BookAuthor{}).
+				Where("book_id = ? AND author_id = ?", book.ID, author.ID).
+				Update("author_order", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		book.StockQuantity = initial
+		if initial == 0 {
+			return nil
+		}
+
+		var warehouse domain.Warehouse
+		if err := tx.First(&warehouse, "code = ?", domain.DefaultWarehouseCode).Error; err != nil {
+			return err
+		}
+		level := &domain.WarehouseStock{BookID: book.ID, WarehouseID: warehouse.ID, Quantity: initial}
+		if err := tx.Create(level).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.StockMovement{
+			BookID:      book.ID,
+			WarehouseID: &warehouse.ID,
+			Delta:       initial,
+			Reason:      domain.StockReasonRestock,
+			ReferenceID: "initial-stock",
+		}).Error
+	})
 }
 
 func (r *bookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Book, error) {
@@ -33,6 +76,9 @@ func (r *bookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Bo
 	if err != nil {
 		return nil, err
 	}
+	if err := r.orderAuthors(ctx, &book); err != nil {
+		return nil, err
+	}
 	return &book, nil
 }
 
@@ -46,66 +92,498 @@ func (r *bookRepository) FindByISBN(ctx context.Context, isbn string) (*domain.B
 	if err != nil {
 		return nil, err
 	}
+	if err := r.orderAuthors(ctx, &book); err != nil {
+		return nil, err
+	}
 	return &book, nil
 }
 
-func (r *bookRepository) FindAll(ctx context.Context, limit, offset int, filters map[string]interface{}) ([]domain.Book, int64, error) {
+// orderAuthors sorts a book's preloaded authors by their author_order
+func (r *bookRepository) orderAuthors(ctx context.Context, book *domain.Book) error {
+	if len(book.Authors) < 2 {
+		return nil
+	}
+	var links []domain.BookAuthor
+	err := r.db.WithContext(ctx).
+		Where("book_id = ?", book.ID).
+		Order("author_order").
+		Find(&links).Error
+	if err != nil {
+		return err
+	}
+
+	position := make(map[uuid.UUID]int, len(links))
+	for i, link := range links {
+		position[link.AuthorID] = i
+	}
+	sort.SliceStable(book.Authors, func(i, j int) bool {
+		return position[book.Authors[i].ID] < position[book.Authors[j].ID]
+	})
+	return nil
+}
+
+func (r *bookRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Book, error) {
+	var books []domain.Book
+	err := r.db.WithContext(ctx).
+		Preload("Authors").
+		Preload("Categories").
+		Preload("Publisher").
+		Where("id IN ?", ids).
+		Find(&books).Error
+	return books, err
+}
+
+// FindAll returns one page of books. With a keyset the page continues from
+// that row instead of skipping offset rows; Before pages are returned in
+// listing order.
+func (r *bookRepository) FindAll(ctx context.Context, limit, offset int, keyset *domain.Keyset, filters map[string]interface{}) ([]domain.Book, error) {
 	var books []domain.Book
+
+	query := applyBookFilters(r.db.WithContext(ctx).Model(&domain.Book{}), filters)
+	search, searching := filters["q"]
+	order, _ := filters["sort"].(domain.BookSort)
+
+	// Full-text matches are ranked by relevance and get a highlighted snippet.
+	// Otherwise only the books columns are selected, since search_rank and
+	// highlight exist in search results only.
+	if !searching {
+		query = query.Select("books.*")
+	} else {
+		query = query.
+			Select(`books.*,
+				ts_rank_cd(books.search_vector, book_search_query(?)) AS search_rank,
+				ts_headline(book_search_config(books.language), coalesce(nullif(books.description, ''), books.title),
+					book_search_query(?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS highlight`,
+				search, search)
+	}
+
+	reverse := false
+	if keyset != nil {
+		// search_rank is an output alias, so filters repeat the expression
+		column := gorm.Expr(bookSortColumn(order.Field))
+		if order.Field == domain.BookSortRelevance {
+			column = gorm.Expr("ts_rank_cd(books.search_vector, book_search_query(?))", search)
+		}
+		condition, args := keysetCondition(column, *keyset)
+		query = query.Where(condition, args...)
+		reverse = keyset.Before
+	} else {
+		query = query.Offset(offset)
+	}
+
+	// Fetch the page with preloaded relationships
+	err := query.
+		Preload("Authors").
+		Preload("Categories").
+		Preload("Publisher").
+		Limit(limit).
+		Order(bookOrder(order, reverse)).
+		Find(&books).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if reverse {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+	}
+	return books, nil
+}
+
+func (r *bookRepository) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
 	var total int64
+	err := applyBookFilters(r.db.WithContext(ctx).Model(&domain.Book{}), filters).
+		Count(&total).Error
+	return total, err
+}
 
-	query := r.db.WithContext(ctx).Model(&domain.Book{})
+// bookSortColumns maps the sort fields to the expressions they order by
+var bookSortColumns = map[string]string{
+	domain.BookSortCreatedAt:       "books.created_at",
+	domain.BookSortPrice:           "books.price",
+	domain.BookSortTitle:           "lower(books.title)",
+	domain.BookSortPublicationDate: "books.publication_date",
+	domain.BookSortRelevance:       "search_rank",
+}
 
-	// Apply filters
-	if categoryID, ok := filters["category_id"]; ok {
-		query = query.Joins("JOIN book_categories ON book_categories.book_id = books.id").
-			Where("book_categories.category_id = ?", categoryID)
+// bookSortColumn returns the expression a sort field orders by, defaulting
+// to the creation time
+func bookSortColumn(field string) string {
+	if column, ok := bookSortColumns[field]; ok {
+		return column
 	}
+	return bookSortColumns[domain.BookSortCreatedAt]
+}
 
-	if authorID, ok := filters["author_id"]; ok {
-		query = query.Joins("JOIN book_authors ON book_authors.book_id = books.id").
-			Where("book_authors.author_id = ?", authorID)
+// bookOrder builds the ORDER BY clause for a sort, defaulting to newest
+// first. Books without a publication date always sort last, and the ID
+// breaks ties so pages don't overlap. reverse flips the whole order for
+// walking backwards from a cursor.
+func bookOrder(order domain.BookSort, reverse bool) string {
+	descending := order.Descending
+	if _, ok := bookSortColumns[order.Field]; !ok {
+		descending = true
 	}
 
-	if title, ok := filters["title"]; ok {
-		query = query.Where("title ILIKE ?", fmt.Sprintf("%%%s%%", title))
+	direction, nulls, tiebreak := "ASC", "NULLS LAST", "books.id"
+	if descending != reverse {
+		direction = "DESC"
 	}
+	if reverse {
+		nulls, tiebreak = "NULLS FIRST", "books.id DESC"
+	}
+	return fmt.Sprintf("%s %s %s, %s", bookSortColumn(order.Field), direction, nulls, tiebreak)
+}
 
-	if minPrice, ok := filters["min_price"]; ok {
-		query = query.Where("price >= ?", minPrice)
+// keysetCondition matches the rows after the keyset row in listing order, or
+// before it for Before keysets. It mirrors bookOrder: the sort column with
+// missing values last, then the ID ascending.
+func keysetCondition(column clause.Expr, keyset domain.Keyset) (string, []interface{}) {
+	if keyset.Value == nil {
+		if keyset.Before {
+			return "(? IS NOT NULL OR books.id < ?)", []interface{}{column, keyset.ID}
+		}
+		return "(? IS NULL AND books.id > ?)", []interface{}{column, keyset.ID}
 	}
 
-	if maxPrice, ok := filters["max_price"]; ok {
-		query = query.Where("price <= ?", maxPrice)
+	// Moving forward means a larger value for ascending sorts
+	cmp, idCmp := ">", ">"
+	if keyset.Sort.Descending != keyset.Before {
+		cmp = "<"
+	}
+	if keyset.Before {
+		idCmp = "<"
+	}
+
+	condition := fmt.Sprintf("? %s ? OR (? = ? AND books.id %s ?)", cmp, idCmp)
+	args := []interface{}{column, keyset.Value, column, keyset.Value, keyset.ID}
+	if !keyset.Before {
+		condition += " OR ? IS NULL"
+		args = append(args, column)
+	}
+	return "(" + condition + ")", args
+}
+
+// maxFacetValues caps how many values are returned per facet
+const maxFacetValues = 50
+
+// facetFilters maps each facet to the filters it ignores, so a facet's counts
+// show what selecting another value would return
+var facetFilters = map[string][]string{
+	domain.FacetCategory:  {"category_id"},
+	domain.FacetAuthor:    {"author_id"},
+	domain.FacetPublisher: {"publisher_id"},
+	domain.FacetFormat:    {"format"},
+	domain.FacetLanguage:  {"language"},
+	domain.FacetPrice:     {"min_price", "max_price"},
+}
+
+// Facets counts matching books per value of each requested facet. priceBounds
+// are the ascending upper bounds of the price buckets.
+func (r *bookRepository) Facets(ctx context.Context, filters map[string]interface{}, facets []string, priceBounds []float64) (*domain.Facets, error) {
+	result := &domain.Facets{}
+
+	for _, facet := range facets {
+		// Apply every active filter except the facet's own
+		others := make(map[string]interface{}, len(filters))
+		for key, value := range filters {
+			others[key] = value
+		}
+		for _, key := range facetFilters[facet] {
+			delete(others, key)
+		}
+		query := applyBookFilters(r.db.WithContext(ctx).Model(&domain.Book{}), others)
+
+		var err error
+		switch facet {
+		case domain.FacetCategory:
+			result.Categories, err = facetValues(query.
+				Joins("JOIN book_categories AS facet_bc ON facet_bc.book_id = books.id").
+				Joins("JOIN categories AS facet_c ON facet_c.id = facet_bc.category_id"),
+				"facet_c.id::text", "facet_c.name")
+		case domain.FacetAuthor:
+			result.Authors, err = facetValues(query.
+				Joins("JOIN book_authors AS facet_ba ON facet_ba.book_id = books.id").
+				Joins("JOIN authors AS facet_a ON facet_a.id = facet_ba.author_id"),
+				"facet_a.id::text", "facet_a.name")
+		case domain.FacetPublisher:
+			result.Publishers, err = facetValues(query.
+				Joins("JOIN publishers AS facet_p ON facet_p.id = books.publisher_id"),
+				"facet_p.id::text", "facet_p.name")
+		case domain.FacetFormat:
+			result.Formats, err = facetValues(query.Where("books.format <> ''"), "books.format", "")
+		case domain.FacetLanguage:
+			result.Languages, err = facetValues(query.Where("books.language <> ''"), "books.language", "")
+		case domain.FacetPrice:
+			result.Price, err = priceBuckets(query, priceBounds)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s facet: %w", facet, err)
+		}
 	}
 
-	// Count total matching records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	return result, nil
+}
+
+// facetValues groups the filtered books by a value expression and counts them.
+// labelExpr may be empty when the value is its own label.
+func facetValues(query *gorm.DB, valueExpr, labelExpr string) ([]domain.FacetValue, error) {
+	group := valueExpr
+	if labelExpr == "" {
+		labelExpr = "''"
+	} else {
+		group += ", " + labelExpr
 	}
 
-	// Fetch paginated results with preloaded relationships
+	values := []domain.FacetValue{}
 	err := query.
-		Preload("Authors").
-		Preload("Categories").
-		Preload("Publisher").
-		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
-		Find(&books).Error
+		Select(fmt.Sprintf("%s AS value, %s AS label, COUNT(DISTINCT books.id) AS count", valueExpr, labelExpr)).
+		Group(group).
+		Order("count DESC, label, value").
+		Limit(maxFacetValues).
+		Scan(&values).Error
+	return values, err
+}
+
+// priceBuckets counts the filtered books per price bucket, including empty buckets
+func priceBuckets(query *gorm.DB, bounds []float64) ([]domain.PriceBucket, error) {
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := query.
+		Select("width_bucket(books.price, ARRAY[?]::float8[]) AS bucket, COUNT(DISTINCT books.id) AS count", bounds).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// width_bucket returns i for bounds[i-1] <= price < bounds[i]
+	buckets := make([]domain.PriceBucket, len(bounds)+1)
+	for i := range buckets {
+		if i > 0 {
+			buckets[i].Min = bounds[i-1]
+		}
+		if i < len(bounds) {
+			upper := bounds[i]
+			buckets[i].Max = &upper
+		}
+	}
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(buckets) {
+			buckets[row.Bucket].Count = row.Count
+		}
+	}
+	return buckets, nil
+}
+
+// Update saves everything but the stock, which only moves through UpdateStock
+// and reservations so the ledger records every change. It only applies if the
+// stored version still equals book.Version, and reports whether it did; on
+// success book.Version is the new version. Non-nil Authors and Categories
+// replace the book's links, in order, once the update has applied.
+func (r *bookRepository) Update(ctx context.Context, book *domain.Book) (bool, error) {
+	expected := book.Version
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		book.Version = expected + 1
+		result := tx.Model(book).
+			Where("version = ?", expected).
+			Select("*").
+			Omit(clause.Associations, "id", "stock_quantity", "created_at").
+			Updates(book)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
 
-	return books, total, err
+		var authorIDs, categoryIDs []uuid.UUID
+		if book.Authors != nil {
+			authorIDs = make([]uuid.UUID, len(book.Authors))
+			for i, author := range book.Authors {
+				authorIDs[i] = author.ID
+			}
+		}
+		if book.Categories != nil {
+			categoryIDs = make([]uuid.UUID, len(book.Categories))
+			for i, category := range book.Categories {
+				categoryIDs[i] = category.ID
+			}
+		}
+		if err := replaceBookLinks(tx, book.ID, authorIDs, categoryIDs); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	if !applied {
+		book.Version = expected
+	}
+	return applied, err
 }
 
-func (r *bookRepository) Update(ctx context.Context, book *domain.Book) error {
-	return r.db.WithContext(ctx).Save(book).Error
+// Patch sets the patch's columns and bumps the version if it still matches,
+// then replaces whichever link lists the patch carries, in one transaction
+func (r *bookRepository) Patch(ctx context.Context, id uuid.UUID, version int, patch *domain.BookPatch) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		columns := map[string]interface{}{"version": gorm.Expr("version + 1")}
+		for column, value := range patch.Columns {
+			columns[column] = value
+		}
+		result := tx.Model(&domain.Book{}).
+			Where("id = ? AND version = ?", id, version).
+			Updates(columns)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := replaceBookLinks(tx, id, patch.AuthorIDs, patch.CategoryIDs); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// replaceBookLinks replaces a book's authors, numbered in order, and its
+// categories with the given IDs. A nil list leaves those links as they are.
+func replaceBookLinks(tx *gorm.DB, bookID uuid.UUID, authorIDs, categoryIDs []uuid.UUID) error {
+	if authorIDs != nil {
+		if err := tx.Where("book_id = ?", bookID).Delete(&domain.BookAuthor{}).Error; err != nil {
+			return err
+		}
+		links := make([]domain.BookAuthor, len(authorIDs))
+		for i, authorID := range authorIDs {
+			links[i] = domain.BookAuthor{BookID: bookID, AuthorID: authorID, AuthorOrder: i + 1}
+		}
+		if len(links) > 0 {
+			if err := tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+	}
+	if categoryIDs != nil {
+		if err := tx.Where("book_id = ?", bookID).Delete(&domain.BookCategory{}).Error; err != nil {
+			return err
+		}
+		links := make([]domain.BookCategory, len(categoryIDs))
+		for i, categoryID := range categoryIDs {
+			links[i] = domain.BookCategory{BookID: bookID, CategoryID: categoryID}
+		}
+		if len(links) > 0 {
+			if err := tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (r *bookRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&domain.Book{}, "id = ?", id).Error
 }
 
-func (r *bookRepository) UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error {
-	return r.db.WithContext(ctx).Model(&domain.Book{}).
-		Where("id = ?", id).
-		Update("stock_quantity", gorm.Expr("stock_quantity + ?", quantity)).Error
+// UpdateStock applies the movement's delta to the stock at its warehouse only
+// if that stays non-negative, and records it in the ledger in the same
+// transaction. It reports whether the delta was applied, so concurrent
+// decrements can't oversell.
+func (r *bookRepository) UpdateStock(ctx context.Context, movement *domain.StockMovement) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if movement.Delta > 0 {
+			result = tx.Exec(`
+				INSERT INTO warehouse_stock (book_id, warehouse_id, quantity, updated_at)
+				VALUES (?, ?, ?, now())
+				ON CONFLICT (book_id, warehouse_id)
+				DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = now()`,
+				movement.BookID, movement.WarehouseID, movement.Delta)
+		} else {
+			result = tx.Model(&domain.WarehouseStock{}).
+				Where("book_id = ? AND warehouse_id = ? AND quantity + ? >= 0", movement.BookID, movement.WarehouseID, movement.Delta).
+				Update("quantity", gorm.Expr("quantity + ?", movement.Delta))
+		}
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// applyBookFilters adds the WHERE clauses and joins for the supported list filters
+func applyBookFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	// category_id is a single ID or, once expanded with its descendants, a list.
+	// Subqueries keep books in several matching categories from repeating and
+	// keep the selected columns those of books.
+	switch categoryID := filters["category_id"].(type) {
+	case uuid.UUID:
+		query = query.Where("books.id IN (SELECT book_id FROM book_categories WHERE category_id = ?)", categoryID)
+	case []uuid.UUID:
+		query = query.Where("books.id IN (SELECT book_id FROM book_categories WHERE category_id IN ?)", categoryID)
+	}
+
+	if authorID, ok := filters["author_id"]; ok {
+		query = query.Where("books.id IN (SELECT book_id FROM book_authors WHERE author_id = ?)", authorID)
+	}
+
+	if publisherID, ok := filters["publisher_id"]; ok {
+		query = query.Where("books.publisher_id = ?", publisherID)
+	}
+
+	if format, ok := filters["format"]; ok {
+		query = query.Where("books.format = ?", format)
+	}
+
+	if language, ok := filters["language"]; ok {
+		query = query.Where("books.language = ?", language)
+	}
+
+	if isbns, ok := filters["isbn"]; ok {
+		query = query.Where("books.isbn IN ?", isbns)
+	}
+
+	if title, ok := filters["title"]; ok {
+		query = query.Where("books.title ILIKE ?", fmt.Sprintf("%%%s%%", title))
+	}
+
+	if search, ok := filters["q"]; ok {
+		query = query.Where("books.search_vector @@ book_search_query(?)", search)
+	}
+
+	if minPrice, ok := filters["min_price"]; ok {
+		query = query.Where("books.price >= ?", minPrice)
+	}
+
+	if maxPrice, ok := filters["max_price"]; ok {
+		query = query.Where("books.price <= ?", maxPrice)
+	}
+
+	if from, ok := filters["published_from"]; ok {
+		query = query.Where("books.publication_date >= ?", from)
+	}
+
+	if to, ok := filters["published_to"]; ok {
+		query = query.Where("books.publication_date <= ?", to)
+	}
+
+	if inStock, ok := filters["in_stock"].(bool); ok {
+		if inStock {
+			query = query.Where("books.stock_quantity > 0")
+		} else {
+			query = query.Where("books.stock_quantity = 0")
+		}
+	}
+
+	return query
 }
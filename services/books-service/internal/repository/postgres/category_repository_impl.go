@@ -46,6 +46,12 @@ func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*doma
 	return &category, nil
 }
 
+func (r *categoryRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&categories).Error
+	return categories, err
+}
+
 func (r *categoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
 	var categories []domain.Category
 	err := r.db.WithContext(ctx).
@@ -62,3 +68,49 @@ func (r *categoryRepository) Update(ctx context.Context, category *domain.Catego
 func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&domain.Category{}, "id = ?", id).Error
 }
+
+// FindDescendantIDs returns the IDs of every category below the given one,
+// walking the parent_id hierarchy with a recursive CTE
+func (r *categoryRepository) FindDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM categories WHERE parent_id = ?
+			UNION
+			SELECT c.id FROM categories c
+			JOIN descendants d ON c.parent_id = d.id
+		)
+		SELECT id FROM descendants`, id).
+		Scan(&ids).Error
+	return ids, err
+}
+
+func (r *categoryRepository) CountBooks(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.BookCategory{}).
+		Where("category_id IN ?", ids).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepository) FindBookIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var bookIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&domain.BookCategory{}).
+		Distinct("book_id").
+		Where("category_id IN ?", ids).
+		Pluck("book_id", &bookIDs).Error
+	return bookIDs, err
+}
+
+// DeleteTree removes the given categories together with their book links
+// in a single transaction. Books themselves are left untouched.
+func (r *categoryRepository) DeleteTree(ctx context.Context, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id IN ?", ids).Delete(&domain.BookCategory{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&domain.Category{}).Error
+	})
+}
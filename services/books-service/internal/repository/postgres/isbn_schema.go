@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/pkg/isbn"
+	"gorm.io/gorm"
+)
+
+// unnormalizedISBNs matches books whose ISBN isn't yet a bare ISBN-13 or that
+// are missing the ISBN-10 of a 978- ISBN
+const unnormalizedISBNs = `isbn !~ '^97[89][0-9]{10}$' OR (isbn LIKE '978%' AND coalesce(isbn10, '') = '')`
+
+// normalizeISBN rewrites one book's ISBN unless another book already holds the
+// normalized form
+const normalizeISBN = `
+	UPDATE books SET isbn = ?, isbn10 = ?, version = version + 1
+	WHERE id = ? AND NOT EXISTS (SELECT 1 FROM books other WHERE other.isbn = ? AND other.id <> books.id)`
+
+// MigrateISBNs stores the ISBNs of books created before ISBNs were validated
+// as bare ISBN-13s and fills in their ISBN-10s. ISBNs that fail their
+// checksum, or that normalize to another book's ISBN, are left as they are to
+// be fixed by hand, so rerunning it only revisits those.
+func MigrateISBNs(db *gorm.DB) error {
+	var books []domain.Book
+	return db.Select("id", "isbn", "isbn10").
+		Where(unnormalizedISBNs).
+		FindInBatches(&books, 500, func(tx *gorm.DB, batch int) error {
+			for _, book := range books {
+				isbn13, err := isbn.Normalize(book.ISBN)
+				if err != nil {
+					continue
+				}
+				isbn10, _ := isbn.ToISBN10(isbn13)
+				if err := db.Exec(normalizeISBN, isbn13, isbn10, book.ID, isbn13).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type publisherRepository struct {
+	db *gorm.DB
+}
+
+// NewPublisherRepository creates a new instance of PublisherRepository
+func NewPublisherRepository(db *gorm.DB) repository.PublisherRepository {
+	return &publisherRepository{db: db}
+}
+
+func (r *publisherRepository) Create(ctx context.Context, publisher *domain.Publisher) error {
+	return r.db.WithContext(ctx).Create(publisher).Error
+}
+
+func (r *publisherRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Publisher, error) {
+	var publisher domain.Publisher
+	err := r.db.WithContext(ctx).First(&publisher, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) FindByName(ctx context.Context, name string) (*domain.Publisher, error) {
+	var publisher domain.Publisher
+	err := r.db.WithContext(ctx).
+		Where("lower(name) = lower(?)", name).
+		Order("created_at").
+		First(&publisher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) FindAll(ctx context.Context, limit, offset int) ([]domain.Publisher, int64, error) {
+	var publishers []domain.Publisher
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Publisher{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Limit(limit).
+		Offset(offset).
+		Order("name ASC").
+		Find(&publishers).Error
+
+	return publishers, total, err
+}
+
+func (r *publisherRepository) Update(ctx context.Context, publisher *domain.Publisher) error {
+	return r.db.WithContext(ctx).Save(publisher).Error
+}
+
+func (r *publisherRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.Publisher{}, "id = ?", id).Error
+}
+
+func (r *publisherRepository) CountBooks(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Where("publisher_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *publisherRepository) FindBookIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Where("publisher_id = ?", id).
+		Pluck("id", &ids).Error
+	return ids, err
+}
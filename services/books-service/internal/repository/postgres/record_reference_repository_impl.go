@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type recordReferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewRecordReferenceRepository creates a new instance of RecordReferenceRepository
+func NewRecordReferenceRepository(db *gorm.DB) repository.RecordReferenceRepository {
+	return &recordReferenceRepository{db: db}
+}
+
+func (r *recordReferenceRepository) FindByReference(ctx context.Context, reference string) (*domain.BookRecordReference, error) {
+	var record domain.BookRecordReference
+	err := r.db.WithContext(ctx).First(&record, "reference = ?", reference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save points the reference at the book, replacing any book it pointed at
+func (r *recordReferenceRepository) Save(ctx context.Context, reference string, bookID uuid.UUID) error {
+	record := &domain.BookRecordReference{Reference: reference, BookID: bookID}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "reference"}},
+			DoUpdates: clause.AssignmentColumns([]string{"book_id", "updated_at"}),
+		}).
+		Create(record).Error
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+func NewReservationRepository(db *gorm.DB) repository.ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+// Hold decrements stock only if enough is left, so two concurrent holds on
+// the last copy can't both succeed. Without a warehouse, the whole quantity is
+// taken from the warehouse with the most stock. A user's holds are counted
+// under a per-user advisory lock so concurrent requests can't exceed maxHeld.
+func (r *reservationRepository) Hold(ctx context.Context, reservation *domain.Reservation, maxHeld int) (bool, error) {
+	held := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", reservation.UserID.String()).Error; err != nil {
+			return err
+		}
+		var active int64
+		err := tx.Model(&domain.Reservation{}).
+			Where("user_id = ? AND status = ? AND expires_at > now()", reservation.UserID, domain.ReservationHeld).
+			Count(&active).Error
+		if err != nil {
+			return err
+		}
+		if active >= int64(maxHeld) {
+			return repository.ErrTooManyHolds
+		}
+
+		var warehouse interface{} = reservation.WarehouseID
+		if reservation.WarehouseID == uuid.Nil {
+			warehouse = tx.Raw(`
+				SELECT warehouse_id FROM warehouse_stock
+				WHERE book_id = ? AND quantity >= ?
+				ORDER BY quantity DESC, warehouse_id
+				LIMIT 1
+				FOR UPDATE`,
+				reservation.BookID, reservation.Quantity)
+		}
+
+		var warehouseIDs []uuid.UUID
+		err = tx.Raw(`
+			UPDATE warehouse_stock SET quantity = quantity - ?, updated_at = now()
+			WHERE book_id = ? AND warehouse_id = (?) AND quantity >= ?
+			RETURNING warehouse_id`,
+			reservation.Quantity, reservation.BookID, warehouse, reservation.Quantity).
+			Scan(&warehouseIDs).Error
+		if err != nil || len(warehouseIDs) == 0 {
+			return err
+		}
+
+		reservation.WarehouseID = warehouseIDs[0]
+		reservation.Status = domain.ReservationHeld
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(reservationMovement(reservation, -reservation.Quantity)).Error; err != nil {
+			return err
+		}
+		held = true
+		return nil
+	})
+	return held, err
+}
+
+func (r *reservationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := r.db.WithContext(ctx).First(&reservation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Confirm turns the held stock into a sale. The ledger returns the hold and
+// records the sale, so per-reason totals stay meaningful while stock is
+// unchanged.
+func (r *reservationRepository) Confirm(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	confirmed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Reservation{}).
+			Where("id = ? AND status = ? AND expires_at > now()", reservation.ID, domain.ReservationHeld).
+			Update("status", domain.ReservationConfirmed)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		sale := reservationMovement(reservation, -reservation.Quantity)
+		sale.Reason = domain.StockReasonSale
+		movements := []*domain.StockMovement{
+			reservationMovement(reservation, reservation.Quantity),
+			sale,
+		}
+		if err := tx.Create(movements).Error; err != nil {
+			return err
+		}
+		confirmed = true
+		return nil
+	})
+	if err != nil || !confirmed {
+		return false, err
+	}
+	reservation.Status = domain.ReservationConfirmed
+	return true, nil
+}
+
+// Release flips the status, restocks and records the movement in one
+// statement so a release racing the expiry sweep returns the quantity
+// exactly once
+func (r *reservationRepository) Release(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	var bookIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH released AS (
+			UPDATE reservations SET status = ?, updated_at = now()
+			WHERE id = ? AND status = ?
+			RETURNING id, book_id, warehouse_id, user_id, quantity
+		), movements AS (
+			INSERT INTO stock_movements (id, book_id, warehouse_id, delta, reason, actor_id, reference_id, created_at)
+			SELECT gen_random_uuid(), book_id, warehouse_id, quantity, ?, user_id, id::text, now()
+			FROM released
+		)
+		`+restockWarehouses("released")+`
+		RETURNING book_id`,
+		domain.ReservationReleased, reservation.ID, domain.ReservationHeld, domain.StockReasonReservation).
+		Scan(&bookIDs).Error
+	if err != nil || len(bookIDs) == 0 {
+		return false, err
+	}
+	reservation.Status = domain.ReservationReleased
+	return true, nil
+}
+
+// ReleaseExpired skips rows locked by a concurrent release or sweep, so
+// several instances can sweep at once without blocking each other
+func (r *reservationRepository) ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	var bookIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH expired AS (
+			UPDATE reservations SET status = ?, updated_at = now()
+			WHERE id IN (
+				SELECT id FROM reservations
+				WHERE status = ? AND expires_at <= ?
+				ORDER BY expires_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, book_id, warehouse_id, quantity
+		), movements AS (
+			INSERT INTO stock_movements (id, book_id, warehouse_id, delta, reason, reference_id, created_at)
+			SELECT gen_random_uuid(), book_id, warehouse_id, quantity, ?, id::text, now()
+			FROM expired
+		), restocked AS (
+			`+restockWarehouses("expired")+`
+			RETURNING book_id
+		)
+		SELECT DISTINCT book_id FROM restocked`,
+		domain.ReservationExpired, domain.ReservationHeld, now, limit, domain.StockReasonReservation).
+		Scan(&bookIDs).Error
+	return bookIDs, err
+}
+
+// reservationMovement records a reservation's effect on stock, attributed to
+// the customer who holds it
+func reservationMovement(reservation *domain.Reservation, delta int) *domain.StockMovement {
+	actorID := reservation.UserID
+	warehouseID := reservation.WarehouseID
+	return &domain.StockMovement{
+		BookID:      reservation.BookID,
+		WarehouseID: &warehouseID,
+		Delta:       delta,
+		Reason:      domain.StockReasonReservation,
+		ActorID:     &actorID,
+		ReferenceID: reservation.ID.String(),
+	}
+}
+
+// restockWarehouses returns the held quantities in the named CTE to their
+// warehouses. It upserts because holds placed before warehouses existed may
+// belong to a warehouse the book has no level in yet.
+func restockWarehouses(cte string) string {
+	return `INSERT INTO warehouse_stock (book_id, warehouse_id, quantity, updated_at)
+		SELECT book_id, warehouse_id, SUM(quantity), now()
+		FROM ` + cte + `
+		GROUP BY book_id, warehouse_id
+		ON CONFLICT (book_id, warehouse_id)
+		DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = now()`
+}
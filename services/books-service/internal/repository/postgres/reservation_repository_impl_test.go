@@ -0,0 +1,232 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	postgresql "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// These tests need a PostgreSQL database they may write to and never clean up,
+// since the stock ledger is append-only. Run them with
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=books_test sslmode=disable" \
+//		go test -tags integration ./internal/repository/postgres/
+
+func TestHoldConcurrentLastCopies(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewReservationRepository(db)
+	const stock, customers = 5, 20
+	book := createTestBook(t, db, stock)
+
+	results := holdConcurrently(repo, customers, func() *domain.Reservation {
+		return testReservation(book, uuid.New())
+	}, customers)
+
+	held := 0
+	for _, result := range results {
+		if result.err != nil {
+			t.Fatalf("Hold() error = %v", result.err)
+		}
+		if result.held {
+			held++
+		}
+	}
+	if held != stock {
+		t.Errorf("%d holds succeeded, want %d", held, stock)
+	}
+	assertTestStock(t, db, book, 0)
+}
+
+func TestHoldConcurrentMaxHeld(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewReservationRepository(db)
+	const maxHeld, attempts = 3, 10
+	book := createTestBook(t, db, attempts)
+	user := uuid.New()
+
+	results := holdConcurrently(repo, attempts, func() *domain.Reservation {
+		return testReservation(book, user)
+	}, maxHeld)
+
+	held, refused := 0, 0
+	for _, result := range results {
+		switch {
+		case errors.Is(result.err, repository.ErrTooManyHolds):
+			refused++
+		case result.err != nil:
+			t.Fatalf("Hold() error = %v", result.err)
+		case result.held:
+			held++
+		}
+	}
+	if held != maxHeld || refused != attempts-maxHeld {
+		t.Errorf("%d holds succeeded and %d were refused, want %d and %d", held, refused, maxHeld, attempts-maxHeld)
+	}
+	assertTestStock(t, db, book, attempts-maxHeld)
+}
+
+func TestReleaseRacingExpirySweep(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewReservationRepository(db)
+	ctx := context.Background()
+	const stock = 3
+	book := createTestBook(t, db, stock)
+
+	reservation := testReservation(book, uuid.New())
+	reservation.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+	if held, err := repo.Hold(ctx, reservation, 1); err != nil || !held {
+		t.Fatalf("Hold() = %v, %v", held, err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := repo.Release(ctx, &domain.Reservation{ID: reservation.ID}); err != nil {
+			t.Errorf("Release() error = %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if _, err := repo.ReleaseExpired(ctx, time.Now().UTC(), 100); err != nil {
+			t.Errorf("ReleaseExpired() error = %v", err)
+		}
+	}()
+	wg.Wait()
+
+	// Whichever won, the copy is back exactly once
+	assertTestStock(t, db, book, stock)
+}
+
+type holdResult struct {
+	held bool
+	err  error
+}
+
+// holdConcurrently starts n holds at once, built by reservation, and returns
+// their results
+func holdConcurrently(repo repository.ReservationRepository, n int, reservation func() *domain.Reservation, maxHeld int) []holdResult {
+	results := make([]holdResult, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			held, err := repo.Hold(context.Background(), reservation(), maxHeld)
+			results[i] = holdResult{held: held, err: err}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return results
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgresql.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	err = db.AutoMigrate(
+		&domain.Publisher{},
+		&domain.Book{},
+		&domain.Warehouse{},
+		&domain.WarehouseStock{},
+		&domain.Reservation{},
+		&domain.StockMovement{},
+	)
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := MigrateWarehouses(db); err != nil {
+		t.Fatalf("failed to migrate warehouses: %v", err)
+	}
+	if err := MigrateStockLedger(db); err != nil {
+		t.Fatalf("failed to migrate the stock ledger: %v", err)
+	}
+	return db
+}
+
+// createTestBook creates a book with stock copies in the default warehouse
+func createTestBook(t *testing.T, db *gorm.DB, stock int) uuid.UUID {
+	t.Helper()
+	book := &domain.Book{ISBN: testISBN(), Title: t.Name(), Price: 10, Language: "en"}
+	if err := db.Create(book).Error; err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+
+	var warehouse domain.Warehouse
+	if err := db.Where("code = ?", domain.DefaultWarehouseCode).First(&warehouse).Error; err != nil {
+		t.Fatalf("failed to find the default warehouse: %v", err)
+	}
+	level := &domain.WarehouseStock{BookID: book.ID, WarehouseID: warehouse.ID, Quantity: stock}
+	if err := db.Create(level).Error; err != nil {
+		t.Fatalf("failed to stock book: %v", err)
+	}
+	return book.ID
+}
+
+func testReservation(book, user uuid.UUID) *domain.Reservation {
+	return &domain.Reservation{
+		BookID:    book,
+		UserID:    user,
+		Quantity:  1,
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+}
+
+// assertTestStock checks the book's warehouse level and its total
+func assertTestStock(t *testing.T, db *gorm.DB, book uuid.UUID, want int) {
+	t.Helper()
+	var level, total int
+	if err := db.Model(&domain.WarehouseStock{}).Where("book_id = ?", book).Select("SUM(quantity)").Scan(&level).Error; err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	if err := db.Model(&domain.Book{}).Where("id = ?", book).Select("stock_quantity").Scan(&total).Error; err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	if level != want || total != want {
+		t.Errorf("warehouse stock = %d and book stock = %d, want %d", level, total, want)
+	}
+}
+
+// testISBN returns a random valid ISBN-13, so runs against the same database
+// don't collide
+func testISBN() string {
+	digits := fmt.Sprintf("978%09d", rand.Intn(1e9))
+	sum := 0
+	for i, digit := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digit-'0') * weight
+	}
+	return fmt.Sprintf("%s%d", digits, (10-sum%10)%10)
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchConfigs maps Book.Language codes to Postgres text search configurations.
+// Other languages are indexed with the "simple" configuration (no stemming).
+var searchConfigs = map[string]string{
+	"da": "danish",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+// MigrateSearch creates the books.search_vector column, the functions and
+// triggers that keep it current, and its GIN index. The vector weighs title
+// and ISBN (A), author names (B), publisher name (C) and description (D), and
+// stems title and description using the book's language. It also enables
+// pg_trgm and indexes the columns used for autocomplete.
+func MigrateSearch(db *gorm.DB) error {
+	for _, stmt := range searchSchema() {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchSchema() []string {
+	languages := make([]string, 0, len(searchConfigs))
+	for lang := range searchConfigs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	var cases strings.Builder
+	queries := []string{"websearch_to_tsquery('simple', q)"}
+	for _, lang := range languages {
+		fmt.Fprintf(&cases, "\n\t\tWHEN '%s' THEN '%s'", lang, searchConfigs[lang])
+		queries = append(queries, fmt.Sprintf("websearch_to_tsquery('%s', q)", searchConfigs[lang]))
+	}
+
+	return []string{
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+
+		// "pt-BR" and "pt" both resolve to portuguese
+		`CREATE OR REPLACE FUNCTION book_search_config(lang text) RETURNS regconfig AS $$
+	SELECT (CASE lower(split_part(coalesce(lang, ''), '-', 1))` + cases.String() + `
+		ELSE 'simple'
+	END)::regconfig
+$$ LANGUAGE sql STABLE`,
+
+		// A query matches a book if it matches under any supported stemming,
+		// so the tsquery stays constant and the GIN index can be used
+		`CREATE OR REPLACE FUNCTION book_search_query(q text) RETURNS tsquery AS $$
+	SELECT ` + strings.Join(queries, "\n\t\t|| ") + `
+$$ LANGUAGE sql STABLE`,
+
+		`CREATE OR REPLACE FUNCTION book_search_vector(
+	p_id uuid, p_title text, p_description text, p_isbn text, p_language text, p_publisher_id uuid
+) RETURNS tsvector AS $$
+	SELECT
+		setweight(to_tsvector(book_search_config(p_language), coalesce(p_title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(p_isbn, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce((
+			SELECT string_agg(a.name, ' ')
+			FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+			WHERE ba.book_id = p_id
+		), '')), 'B') ||
+		setweight(to_tsvector('simple', coalesce((
+			SELECT p.name FROM publishers p WHERE p.id = p_publisher_id
+		), '')), 'C') ||
+		setweight(to_tsvector(book_search_config(p_language), coalesce(p_description, '')), 'D')
+$$ LANGUAGE sql STABLE`,
+
+		`CREATE OR REPLACE FUNCTION refresh_book_search_vectors(p_book_ids uuid[]) RETURNS void AS $$
+	UPDATE books
+	SET search_vector = book_search_vector(id, title, description, isbn, language, publisher_id)
+	WHERE id = ANY(p_book_ids)
+$$ LANGUAGE sql`,
+
+		`CREATE OR REPLACE FUNCTION books_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector := book_search_vector(NEW.id, NEW.title, NEW.description, NEW.isbn, NEW.language, NEW.publisher_id);
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`,
+
+		`DROP TRIGGER IF EXISTS books_search_vector_update ON books`,
+		`CREATE TRIGGER books_search_vector_update
+	BEFORE INSERT OR UPDATE OF title, description, isbn, language, publisher_id ON books
+	FOR EACH ROW EXECUTE FUNCTION books_search_vector_trigger()`,
+
+		`CREATE OR REPLACE FUNCTION book_authors_search_trigger() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		PERFORM refresh_book_search_vectors(ARRAY[OLD.book_id]);
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		PERFORM refresh_book_search_vectors(ARRAY[NEW.book_id]);
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`,
+
+		`DROP TRIGGER IF EXISTS book_authors_search_update ON book_authors`,
+		`CREATE TRIGGER book_authors_search_update
+	AFTER INSERT OR UPDATE OR DELETE ON book_authors
+	FOR EACH ROW EXECUTE FUNCTION book_authors_search_trigger()`,
+
+		`CREATE OR REPLACE FUNCTION authors_search_trigger() RETURNS trigger AS $$
+BEGIN
+	PERFORM refresh_book_search_vectors(ARRAY(SELECT book_id FROM book_authors WHERE author_id = NEW.id));
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`,
+
+		`DROP TRIGGER IF EXISTS authors_search_update ON authors`,
+		`CREATE TRIGGER authors_search_update
+	AFTER UPDATE OF name ON authors
+	FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+	EXECUTE FUNCTION authors_search_trigger()`,
+
+		`CREATE OR REPLACE FUNCTION publishers_search_trigger() RETURNS trigger AS $$
+BEGIN
+	PERFORM refresh_book_search_vectors(ARRAY(SELECT id FROM books WHERE publisher_id = NEW.id));
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`,
+
+		`DROP TRIGGER IF EXISTS publishers_search_update ON publishers`,
+		`CREATE TRIGGER publishers_search_update
+	AFTER UPDATE OF name ON publishers
+	FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+	EXECUTE FUNCTION publishers_search_trigger()`,
+
+		// Backfill rows created before search existed
+		`UPDATE books
+	SET search_vector = book_search_vector(id, title, description, isbn, language, publisher_id)
+	WHERE search_vector IS NULL`,
+
+		`CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector)`,
+
+		// Trigram indexes back the typo-tolerant autocomplete
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN (title gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_authors_name_trgm ON authors USING GIN (name gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops)`,
+	}
+}
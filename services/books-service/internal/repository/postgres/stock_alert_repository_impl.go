@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// lowStockSelect lists the books at or below their effective threshold. Books
+// with no threshold of their own and none on their categories never match.
+const lowStockSelect = `
+	SELECT books.id AS book_id, books.title, books.isbn, books.stock_quantity,
+		COALESCE(books.reorder_threshold, category_thresholds.threshold) AS threshold
+	FROM books
+	LEFT JOIN LATERAL (
+		SELECT MAX(categories.reorder_threshold) AS threshold
+		FROM book_categories
+		JOIN categories ON categories.id = book_categories.category_id
+		WHERE book_categories.book_id = books.id
+	) AS category_thresholds ON true
+	WHERE books.stock_quantity <= COALESCE(books.reorder_threshold, category_thresholds.threshold)`
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+// NewStockAlertRepository creates a new instance of StockAlertRepository
+func NewStockAlertRepository(db *gorm.DB) repository.StockAlertRepository {
+	return &stockAlertRepository{db: db}
+}
+
+// FindLowStock returns low books, furthest below their threshold first, with
+// the time their open alert was raised
+func (r *stockAlertRepository) FindLowStock(ctx context.Context, limit, offset int) ([]domain.LowStockItem, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Raw(`SELECT count(*) FROM (` + lowStockSelect + `) AS low`).
+		Scan(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := []domain.LowStockItem{}
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT low.*, stock_alerts.created_at AS alerted_at
+		FROM (`+lowStockSelect+`) AS low
+		LEFT JOIN stock_alerts ON stock_alerts.book_id = low.book_id AND stock_alerts.resolved_at IS NULL
+		ORDER BY low.stock_quantity - low.threshold, low.title, low.book_id
+		LIMIT ? OFFSET ?`, limit, offset).
+		Scan(&items).Error
+	return items, total, err
+}
+
+// Open relies on the unique index over open alerts, so concurrent checkers
+// can't raise the same alert twice
+func (r *stockAlertRepository) Open(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO stock_alerts (id, book_id, stock_quantity, threshold, created_at)
+		SELECT gen_random_uuid(), low.book_id, low.stock_quantity, low.threshold, now()
+		FROM (` + lowStockSelect + `) AS low
+		ON CONFLICT (book_id) WHERE resolved_at IS NULL DO NOTHING`)
+	return result.RowsAffected, result.Error
+}
+
+func (r *stockAlertRepository) ResolveRecovered(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE stock_alerts SET resolved_at = now()
+		WHERE resolved_at IS NULL
+			AND book_id NOT IN (SELECT book_id FROM (` + lowStockSelect + `) AS low)`)
+	return result.RowsAffected, result.Error
+}
+
+// FindUnnotified returns open alerts that haven't reached the logging service
+// yet, oldest first, with their books
+func (r *stockAlertRepository) FindUnnotified(ctx context.Context, limit int) ([]domain.StockAlert, error) {
+	var alerts []domain.StockAlert
+	err := r.db.WithContext(ctx).
+		Preload("Book").
+		Where("notified_at IS NULL AND resolved_at IS NULL").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *stockAlertRepository) MarkNotified(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&domain.StockAlert{}).
+		Where("id = ?", id).
+		Update("notified_at", gorm.Expr("now()")).Error
+}
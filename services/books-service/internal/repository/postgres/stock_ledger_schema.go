@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+)
+
+// stockLedgerSchema makes stock_movements append-only and opens the ledger for
+// books that had stock before it existed, so every book reconciles from the
+// start. The backfill only touches books with no movements, so rerunning it
+// is a no-op.
+var stockLedgerSchema = []string{
+	`CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger
+	LANGUAGE plpgsql AS $$
+	BEGIN
+		RAISE EXCEPTION 'stock_movements is append-only';
+	END
+	$$`,
+
+	`DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements`,
+	`CREATE TRIGGER stock_movements_append_only
+	BEFORE UPDATE OR DELETE ON stock_movements
+	FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only()`,
+
+	`INSERT INTO stock_movements (book_id, delta, reason, reference_id, created_at)
+	SELECT books.id, books.stock_quantity, 'adjustment', 'opening-balance', now()
+	FROM books
+	WHERE books.stock_quantity <> 0
+		AND NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.book_id = books.id)`,
+}
+
+// MigrateStockLedger installs the append-only guard on stock_movements and
+// backfills opening balances
+func MigrateStockLedger(db *gorm.DB) error {
+	for _, stmt := range stockLedgerSchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMovementRepository creates a new instance of StockMovementRepository
+func NewStockMovementRepository(db *gorm.DB) repository.StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+func (r *stockMovementRepository) FindByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]domain.StockMovement, int64, error) {
+	var movements []domain.StockMovement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.StockMovement{}).Where("book_id = ?", bookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+// reconciliationSelect sums each book's ledger next to its stock
+const reconciliationSelect = `
+	SELECT books.id AS book_id,
+		books.stock_quantity,
+		COALESCE(SUM(stock_movements.delta), 0) AS ledger_quantity,
+		books.stock_quantity - COALESCE(SUM(stock_movements.delta), 0) AS difference,
+		books.stock_quantity = COALESCE(SUM(stock_movements.delta), 0) AS consistent
+	FROM books
+	LEFT JOIN stock_movements ON stock_movements.book_id = books.id`
+
+func (r *stockMovementRepository) Reconcile(ctx context.Context, bookID uuid.UUID) (*domain.StockReconciliation, error) {
+	var rows []domain.StockReconciliation
+	err := r.db.WithContext(ctx).Raw(reconciliationSelect+`
+	WHERE books.id = ?
+	GROUP BY books.id`, bookID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &rows[0], nil
+}
+
+// FindDiscrepancies returns the books whose stock doesn't match their ledger,
+// largest difference first
+func (r *stockMovementRepository) FindDiscrepancies(ctx context.Context, limit int) ([]domain.StockReconciliation, error) {
+	rows := []domain.StockReconciliation{}
+	err := r.db.WithContext(ctx).Raw(reconciliationSelect+`
+	GROUP BY books.id
+	HAVING books.stock_quantity <> COALESCE(SUM(stock_movements.delta), 0)
+	ORDER BY abs(books.stock_quantity - COALESCE(SUM(stock_movements.delta), 0)) DESC, books.id
+	LIMIT ?`, limit).
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type suggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository creates a new instance of SuggestionRepository
+func NewSuggestionRepository(db *gorm.DB) repository.SuggestionRepository {
+	return &suggestionRepository{db: db}
+}
+
+// suggestQuery fetches the best trigram matches of each type in one round trip.
+// The <% operator (word similarity above pg_trgm.word_similarity_threshold)
+// is served by the trigram GIN indexes.
+const suggestQuery = `
+(SELECT 'title' AS type, id, title AS text, word_similarity(@q, title) AS score
+	FROM books WHERE @q <% title
+	ORDER BY score DESC, title LIMIT @limit)
+UNION ALL
+(SELECT 'author', id, name, word_similarity(@q, name) AS score
+	FROM authors WHERE @q <% name
+	ORDER BY score DESC, name LIMIT @limit)
+UNION ALL
+(SELECT 'category', id, name, word_similarity(@q, name) AS score
+	FROM categories WHERE @q <% name
+	ORDER BY score DESC, name LIMIT @limit)`
+
+// Suggest returns up to limit matches per type, best first within each type
+func (r *suggestionRepository) Suggest(ctx context.Context, query string, limit int) ([]domain.Suggestion, error) {
+	var suggestions []domain.Suggestion
+	err := r.db.WithContext(ctx).
+		Raw(suggestQuery, map[string]interface{}{"q": query, "limit": limit}).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
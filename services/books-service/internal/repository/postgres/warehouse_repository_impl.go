@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewWarehouseRepository creates a new instance of WarehouseRepository
+func NewWarehouseRepository(db *gorm.DB) repository.WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	return r.db.WithContext(ctx).Create(warehouse).Error
+}
+
+func (r *warehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	err := r.db.WithContext(ctx).First(&warehouse, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) FindByCode(ctx context.Context, code string) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	err := r.db.WithContext(ctx).First(&warehouse, "code = ?", code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) FindAll(ctx context.Context) ([]domain.Warehouse, error) {
+	var warehouses []domain.Warehouse
+	err := r.db.WithContext(ctx).Order("code ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *warehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	return r.db.WithContext(ctx).Save(warehouse).Error
+}
+
+// FindStockByBookID returns a book's stock level at every warehouse that has
+// held it, including levels that are now zero
+func (r *warehouseRepository) FindStockByBookID(ctx context.Context, bookID uuid.UUID) ([]domain.WarehouseStock, error) {
+	levels := []domain.WarehouseStock{}
+	err := r.db.WithContext(ctx).
+		Preload("Warehouse").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stock.warehouse_id").
+		Where("warehouse_stock.book_id = ?", bookID).
+		Order("warehouses.code ASC").
+		Find(&levels).Error
+	return levels, err
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"gorm.io/gorm"
+)
+
+// warehouseSchema creates the default warehouse, moves stock that predates
+// warehouses into it and installs the trigger that keeps books.stock_quantity
+// equal to the sum of a book's warehouse levels. Stock changes leave the
+// book's version alone, so they don't conflict with edits to the book. The
+// backfill runs before the trigger exists so it isn't counted twice, and only
+// touches books without levels, so rerunning it is a no-op.
+var warehouseSchema = []string{
+	`INSERT INTO warehouses (code, name, created_at, updated_at)
+	VALUES ('` + domain.DefaultWarehouseCode + `', 'Main warehouse', now(), now())
+	ON CONFLICT (code) DO NOTHING`,
+
+	`DROP TRIGGER IF EXISTS warehouse_stock_total ON warehouse_stock`,
+
+	`INSERT INTO warehouse_stock (book_id, warehouse_id, quantity, updated_at)
+	SELECT books.id, warehouses.id, books.stock_quantity, now()
+	FROM books
+	JOIN warehouses ON warehouses.code = '` + domain.DefaultWarehouseCode + `'
+	WHERE books.stock_quantity <> 0
+		AND NOT EXISTS (SELECT 1 FROM warehouse_stock WHERE warehouse_stock.book_id = books.id)`,
+
+	`UPDATE reservations
+	SET warehouse_id = (SELECT id FROM warehouses WHERE code = '` + domain.DefaultWarehouseCode + `')
+	WHERE warehouse_id IS NULL`,
+
+	`CREATE OR REPLACE FUNCTION warehouse_stock_total() RETURNS trigger
+	LANGUAGE plpgsql AS $$
+	BEGIN
+		IF TG_OP = 'INSERT' THEN
+			UPDATE books SET stock_quantity = stock_quantity + NEW.quantity, updated_at = now()
+			WHERE id = NEW.book_id;
+		ELSIF TG_OP = 'DELETE' THEN
+			UPDATE books SET stock_quantity = stock_quantity - OLD.quantity, updated_at = now()
+			WHERE id = OLD.book_id;
+		ELSIF NEW.quantity <> OLD.quantity THEN
+			UPDATE books SET stock_quantity = stock_quantity + NEW.quantity - OLD.quantity, updated_at = now()
+			WHERE id = NEW.book_id;
+		END IF;
+		RETURN NULL;
+	END
+	$$`,
+
+	`CREATE TRIGGER warehouse_stock_total
+	AFTER INSERT OR UPDATE OR DELETE ON warehouse_stock
+	FOR EACH ROW EXECUTE FUNCTION warehouse_stock_total()`,
+}
+
+// MigrateWarehouses sets up warehouse stock in one transaction, so a
+// concurrent stock change can't slip in between the backfill and the trigger
+func MigrateWarehouses(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range warehouseSchema {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
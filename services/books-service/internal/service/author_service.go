@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuthorNotFound = errors.New("author not found")
+	ErrAuthorHasBooks = errors.New("author has books assigned")
+)
+
+// AuthorService defines the interface for author business logic
+type AuthorService interface {
+	CreateAuthor(ctx context.Context, author *domain.Author) error
+	GetAuthor(ctx context.Context, id uuid.UUID) (*domain.Author, error)
+	ListAuthors(ctx context.Context, limit, offset int) ([]domain.Author, int64, error)
+	UpdateAuthor(ctx context.Context, author *domain.Author) error
+	DeleteAuthor(ctx context.Context, id uuid.UUID) error
+	ListAuthorBooks(ctx context.Context, id uuid.UUID, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error)
+}
+
+type authorService struct {
+	authorRepo  repository.AuthorRepository
+	bookService BookService
+}
+
+// NewAuthorService creates a new instance of AuthorService
+func NewAuthorService(authorRepo repository.AuthorRepository, bookService BookService) AuthorService {
+	return &authorService{
+		authorRepo:  authorRepo,
+		bookService: bookService,
+	}
+}
+
+func (s *authorService) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	if author == nil {
+		return ErrInvalidInput
+	}
+
+	author.Name = strings.TrimSpace(author.Name)
+	if author.Name == "" {
+		return ErrInvalidInput
+	}
+
+	if err := s.authorRepo.Create(ctx, author); err != nil {
+		return fmt.Errorf("failed to create author: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authorService) GetAuthor(ctx context.Context, id uuid.UUID) (*domain.Author, error) {
+	author, err := s.authorRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthorNotFound
+		}
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+	return author, nil
+}
+
+func (s *authorService) ListAuthors(ctx context.Context, limit, offset int) ([]domain.Author, int64, error) {
+	limit, offset = normalizePagination(limit, offset)
+
+	authors, total, err := s.authorRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	return authors, total, nil
+}
+
+func (s *authorService) UpdateAuthor(ctx context.Context, author *domain.Author) error {
+	if author == nil || author.ID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	author.Name = strings.TrimSpace(author.Name)
+	if author.Name == "" {
+		return ErrInvalidInput
+	}
+
+	existing, err := s.GetAuthor(ctx, author.ID)
+	if err != nil {
+		return err
+	}
+	author.CreatedAt = existing.CreatedAt
+
+	if err := s.authorRepo.Update(ctx, author); err != nil {
+		return fmt.Errorf("failed to update author: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authorService) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetAuthor(ctx, id); err != nil {
+		return err
+	}
+
+	bookCount, err := s.authorRepo.CountBooks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to count books: %w", err)
+	}
+	if bookCount > 0 {
+		return ErrAuthorHasBooks
+	}
+
+	if err := s.authorRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete author: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authorService) ListAuthorBooks(ctx context.Context, id uuid.UUID, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error) {
+	if _, err := s.GetAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if filters == nil {
+		filters = make(map[string]interface{})
+	}
+	filters["author_id"] = id
+
+	return s.bookService.ListBooks(ctx, page, filters)
+}
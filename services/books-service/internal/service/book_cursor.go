@@ -0,0 +1,102 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+// cursorToken is the JSON form of a keyset inside an opaque cursor
+type cursorToken struct {
+	Sort       string          `json:"s"`
+	Descending bool            `json:"d,omitempty"`
+	Value      json.RawMessage `json:"v"`
+	ID         uuid.UUID       `json:"id"`
+	Before     bool            `json:"b,omitempty"`
+}
+
+// encodeCursor turns a keyset into an opaque, URL-safe cursor
+func encodeCursor(keyset domain.Keyset) string {
+	value, err := json.Marshal(keyset.Value)
+	if err != nil {
+		value = []byte("null")
+	}
+	data, _ := json.Marshal(cursorToken{
+		Sort:       keyset.Sort.Field,
+		Descending: keyset.Sort.Descending,
+		Value:      value,
+		ID:         keyset.ID,
+		Before:     keyset.Before,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor produced by encodeCursor, restoring the sort
+// value to the type the sort column compares against
+func decodeCursor(cursor string) (*domain.Keyset, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, ok := bookSortDefaults[token.Sort]; !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	keyset := &domain.Keyset{
+		Sort:   domain.BookSort{Field: token.Sort, Descending: token.Descending},
+		ID:     token.ID,
+		Before: token.Before,
+	}
+	if len(token.Value) == 0 || string(token.Value) == "null" {
+		return keyset, nil
+	}
+
+	switch token.Sort {
+	case domain.BookSortCreatedAt, domain.BookSortPublicationDate:
+		var value time.Time
+		err = json.Unmarshal(token.Value, &value)
+		keyset.Value = value
+	case domain.BookSortPrice, domain.BookSortRelevance:
+		var value float64
+		err = json.Unmarshal(token.Value, &value)
+		keyset.Value = value
+	case domain.BookSortTitle:
+		var value string
+		err = json.Unmarshal(token.Value, &value)
+		keyset.Value = value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return keyset, nil
+}
+
+// bookKeyset captures a book's position in a listing sorted by order
+func bookKeyset(book domain.Book, order domain.BookSort, before bool) domain.Keyset {
+	keyset := domain.Keyset{Sort: order, ID: book.ID, Before: before}
+	switch order.Field {
+	case domain.BookSortCreatedAt:
+		keyset.Value = book.CreatedAt
+	case domain.BookSortPrice:
+		keyset.Value = book.Price
+	case domain.BookSortTitle:
+		// Matches the lower(title) the listing sorts by
+		keyset.Value = strings.ToLower(book.Title)
+	case domain.BookSortPublicationDate:
+		if book.PublicationDate != nil {
+			keyset.Value = *book.PublicationDate
+		}
+	case domain.BookSortRelevance:
+		keyset.Value = book.SearchRank
+	}
+	return keyset
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+// maxBookLinks caps how many authors or categories a patch may link
+const maxBookLinks = 100
+
+// bookPatch collects a merge patch document into column updates while
+// applying the same changes to a copy of the stored book
+type bookPatch struct {
+	book  *domain.Book
+	patch *domain.BookPatch
+}
+
+// decodeBookPatch validates an RFC 7386 merge patch against book and returns
+// the changes it makes. Fields left out of the document stay as they are, and
+// null clears a field where the book allows it. Authors and categories are
+// replaced by the author_ids and category_ids lists.
+func decodeBookPatch(book *domain.Book, document []byte) (*domain.BookPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(document, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidInput)
+	}
+
+	p := &bookPatch{
+		book:  book,
+		patch: &domain.BookPatch{Columns: map[string]interface{}{}},
+	}
+	for field, value := range fields {
+		if err := p.apply(field, value); err != nil {
+			return nil, err
+		}
+	}
+	return p.patch, nil
+}
+
+func (p *bookPatch) apply(field string, value json.RawMessage) error {
+	null := string(value) == "null"
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s %s", ErrInvalidInput, field, reason)
+	}
+
+	switch field {
+	case "isbn":
+		var text string
+		if null || json.Unmarshal(value, &text) != nil {
+			return invalid("must be a string")
+		}
+		isbn13, isbn10, err := canonicalISBN(text)
+		if err != nil {
+			return err
+		}
+		p.book.ISBN, p.book.ISBN10 = isbn13, isbn10
+		p.patch.Columns["isbn"] = isbn13
+		p.patch.Columns["isbn10"] = isbn10
+
+	case "title":
+		var title string
+		if null || json.Unmarshal(value, &title) != nil || strings.TrimSpace(title) == "" {
+			return invalid("must be a non-empty string")
+		}
+		p.book.Title = title
+		p.patch.Columns[field] = title
+
+	case "description", "format", "cover_image_url":
+		var text string
+		if !null && json.Unmarshal(value, &text) != nil {
+			return invalid("must be a string or null")
+		}
+		if field == "format" && len(text) > 50 {
+			return invalid("must be at most 50 characters")
+		}
+		switch field {
+		case "description":
+			p.book.Description = text
+		case "format":
+			p.book.Format = text
+		case "cover_image_url":
+			p.book.CoverImageURL = text
+		}
+		p.patch.Columns[field] = text
+
+	case "language":
+		var language string
+		if null || json.Unmarshal(value, &language) != nil || language == "" || len(language) > 10 {
+			return invalid("must be a language code of at most 10 characters")
+		}
+		p.book.Language = language
+		p.patch.Columns[field] = language
+
+	case "publisher_id":
+		var publisherID *uuid.UUID
+		if err := json.Unmarshal(value, &publisherID); err != nil {
+			return invalid("must be a UUID or null")
+		}
+		p.book.PublisherID = publisherID
+		p.book.Publisher = nil
+		p.patch.Columns[field] = publisherID
+
+	case "publication_date":
+		var date *time.Time
+		if err := json.Unmarshal(value, &date); err != nil {
+			return invalid("must be an RFC 3339 timestamp or null")
+		}
+		p.book.PublicationDate = date
+		p.patch.Columns[field] = date
+
+	case "pages":
+		var pages int
+		if !null && (json.Unmarshal(value, &pages) != nil || pages < 0) {
+			return invalid("must be a non-negative integer or null")
+		}
+		p.book.Pages = pages
+		p.patch.Columns[field] = pages
+
+	case "price":
+		var price float64
+		if null || json.Unmarshal(value, &price) != nil || price < 0 {
+			return invalid("must be a non-negative number")
+		}
+		p.book.Price = price
+		p.patch.Columns[field] = price
+
+	case "reorder_threshold":
+		var threshold *int
+		if json.Unmarshal(value, &threshold) != nil || (threshold != nil && *threshold < 0) {
+			return invalid("must be a non-negative integer or null")
+		}
+		p.book.ReorderThreshold = threshold
+		p.patch.Columns[field] = threshold
+
+	case "metadata":
+		return p.applyMetadata(value, null, invalid)
+
+	case "author_ids", "category_ids":
+		ids, err := decodeLinkIDs(value)
+		if err != nil {
+			return invalid(err.Error())
+		}
+		if field == "author_ids" {
+			p.patch.AuthorIDs = ids
+		} else {
+			p.patch.CategoryIDs = ids
+		}
+
+	case "stock_quantity":
+		return invalid("can only change through the stock endpoint")
+
+	case "version":
+		return invalid("is checked with the If-Match header")
+
+	default:
+		return fmt.Errorf("%w: %s can't be patched", ErrInvalidInput, field)
+	}
+	return nil
+}
+
+// applyMetadata merges the patch into the stored metadata document, so
+// members can be added, replaced or removed one at a time
+func (p *bookPatch) applyMetadata(value json.RawMessage, null bool, invalid func(string) error) error {
+	if null {
+		p.book.Metadata = ""
+		p.patch.Columns["metadata"] = nil
+		return nil
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal(value, &patch); err != nil {
+		return invalid("must be valid JSON")
+	}
+	var current interface{}
+	if p.book.Metadata != "" {
+		if err := json.Unmarshal([]byte(p.book.Metadata), &current); err != nil {
+			current = nil
+		}
+	}
+
+	merged, err := json.Marshal(mergePatch(current, patch))
+	if err != nil {
+		return invalid("must be valid JSON")
+	}
+	p.book.Metadata = string(merged)
+	p.patch.Columns["metadata"] = p.book.Metadata
+	return nil
+}
+
+// mergePatch applies an RFC 7386 merge patch to a decoded JSON value
+func mergePatch(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	merged, ok := target.(map[string]interface{})
+	if !ok {
+		merged = map[string]interface{}{}
+	}
+	for key, value := range members {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = mergePatch(merged[key], value)
+	}
+	return merged
+}
+
+// decodeLinkIDs reads a list of distinct IDs; null means an empty list
+func decodeLinkIDs(value json.RawMessage) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := json.Unmarshal(value, &ids); err != nil {
+		return nil, errors.New("must be a list of UUIDs")
+	}
+	if len(ids) > maxBookLinks {
+		return nil, fmt.Errorf("must list at most %d IDs", maxBookLinks)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("lists %s more than once", id)
+		}
+		seen[id] = true
+	}
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	return ids, nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+func TestMergePatch(t *testing.T) {
+	// The examples from RFC 7386, appendix A
+	tests := []struct {
+		target string
+		patch  string
+		want   string
+	}{
+		{target: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{target: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{target: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{target: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{target: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{target: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{target: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{target: `{"e":null}`, patch: `{"a":1}`, want: `{"a":1,"e":null}`},
+		{target: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{target: `null`, patch: `{"a":1}`, want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.patch, func(t *testing.T) {
+			var target, patch interface{}
+			if err := json.Unmarshal([]byte(tt.target), &target); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(mergePatch(target, patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("mergePatch(%s, %s) = %s, want %s", tt.target, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBookPatch(t *testing.T) {
+	publisherID := uuid.New()
+	authorID := uuid.New()
+	published := time.Date(2015, 10, 26, 0, 0, 0, 0, time.UTC)
+	threshold := 5
+
+	tests := []struct {
+		name     string
+		document string
+		// wantErr is true when the patch must be rejected as invalid input
+		wantErr bool
+		// wantColumns are the columns the patch must set, and no others
+		wantColumns map[string]interface{}
+		check       func(t *testing.T, book *domain.Book, patch *domain.BookPatch)
+	}{
+		{
+			name:        "empty patch changes nothing",
+			document:    `{}`,
+			wantColumns: map[string]interface{}{},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if !reflect.DeepEqual(book, testPatchBook(publisherID, published, threshold)) {
+					t.Errorf("book changed: %+v", book)
+				}
+				if patch.AuthorIDs != nil || patch.CategoryIDs != nil {
+					t.Errorf("links = %v, %v, want nil", patch.AuthorIDs, patch.CategoryIDs)
+				}
+			},
+		},
+		{
+			name:        "absent fields are kept",
+			document:    `{"title":"New Title"}`,
+			wantColumns: map[string]interface{}{"title": "New Title"},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if book.Title != "New Title" || book.Description != "A description" || book.Pages != 320 {
+					t.Errorf("book = %+v", book)
+				}
+			},
+		},
+		{
+			name:        "null clears a string",
+			document:    `{"description":null}`,
+			wantColumns: map[string]interface{}{"description": ""},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if book.Description != "" {
+					t.Errorf("description = %q, want empty", book.Description)
+				}
+			},
+		},
+		{
+			name:        "null clears pages",
+			document:    `{"pages":null}`,
+			wantColumns: map[string]interface{}{"pages": 0},
+		},
+		{
+			name:        "null clears the publisher",
+			document:    `{"publisher_id":null}`,
+			wantColumns: map[string]interface{}{"publisher_id": (*uuid.UUID)(nil)},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if book.PublisherID != nil {
+					t.Errorf("publisher_id = %v, want nil", book.PublisherID)
+				}
+			},
+		},
+		{
+			name:        "null clears the publication date",
+			document:    `{"publication_date":null}`,
+			wantColumns: map[string]interface{}{"publication_date": (*time.Time)(nil)},
+		},
+		{
+			name:        "null clears the reorder threshold",
+			document:    `{"reorder_threshold":null}`,
+			wantColumns: map[string]interface{}{"reorder_threshold": (*int)(nil)},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if book.ReorderThreshold != nil {
+					t.Errorf("reorder_threshold = %d, want nil", *book.ReorderThreshold)
+				}
+			},
+		},
+		{
+			name:        "zero reorder threshold is kept apart from null",
+			document:    `{"reorder_threshold":0}`,
+			wantColumns: map[string]interface{}{"reorder_threshold": intPtr(0)},
+		},
+		{
+			name:        "isbn is stored in both forms",
+			document:    `{"isbn":"0-306-40615-2"}`,
+			wantColumns: map[string]interface{}{"isbn": "9780306406157", "isbn10": "0306406152"},
+		},
+		{
+			name:        "null author list unlinks every author",
+			document:    `{"author_ids":null}`,
+			wantColumns: map[string]interface{}{},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if patch.AuthorIDs == nil || len(patch.AuthorIDs) != 0 {
+					t.Errorf("author_ids = %#v, want an empty list", patch.AuthorIDs)
+				}
+				if patch.CategoryIDs != nil {
+					t.Errorf("category_ids = %v, want nil", patch.CategoryIDs)
+				}
+			},
+		},
+		{
+			name:        "author list replaces the authors",
+			document:    `{"author_ids":["` + authorID.String() + `"]}`,
+			wantColumns: map[string]interface{}{},
+			check: func(t *testing.T, book *domain.Book, patch *domain.BookPatch) {
+				if !reflect.DeepEqual(patch.AuthorIDs, []uuid.UUID{authorID}) {
+					t.Errorf("author_ids = %v, want [%s]", patch.AuthorIDs, authorID)
+				}
+			},
+		},
+		{
+			name:        "metadata is merged",
+			document:    `{"metadata":{"series":null,"edition":2,"tags":{"new":true}}}`,
+			wantColumns: map[string]interface{}{"metadata": `{"edition":2,"tags":{"new":true,"old":true}}`},
+		},
+		{
+			name:        "null metadata clears it",
+			document:    `{"metadata":null}`,
+			wantColumns: map[string]interface{}{"metadata": nil},
+		},
+		{name: "null title", document: `{"title":null}`, wantErr: true},
+		{name: "blank title", document: `{"title":"  "}`, wantErr: true},
+		{name: "null isbn", document: `{"isbn":null}`, wantErr: true},
+		{name: "invalid isbn", document: `{"isbn":"9780306406158"}`, wantErr: true},
+		{name: "null price", document: `{"price":null}`, wantErr: true},
+		{name: "negative price", document: `{"price":-1}`, wantErr: true},
+		{name: "null language", document: `{"language":null}`, wantErr: true},
+		{name: "negative pages", document: `{"pages":-1}`, wantErr: true},
+		{name: "negative reorder threshold", document: `{"reorder_threshold":-1}`, wantErr: true},
+		{name: "long format", document: `{"format":"` + strings.Repeat("x", 51) + `"}`, wantErr: true},
+		{name: "repeated author", document: `{"author_ids":["` + authorID.String() + `","` + authorID.String() + `"]}`, wantErr: true},
+		{name: "stock quantity", document: `{"stock_quantity":3}`, wantErr: true},
+		{name: "version", document: `{"version":3}`, wantErr: true},
+		{name: "unknown field", document: `{"rating":5}`, wantErr: true},
+		{name: "null document", document: `null`, wantErr: true},
+		{name: "array document", document: `[]`, wantErr: true},
+		{name: "malformed document", document: `{"title":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := testPatchBook(publisherID, published, threshold)
+			patch, err := decodeBookPatch(book, []byte(tt.document))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("decodeBookPatch(%s) error = %v, want ErrInvalidInput", tt.document, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeBookPatch(%s) error = %v", tt.document, err)
+			}
+
+			if !reflect.DeepEqual(patch.Columns, tt.wantColumns) {
+				t.Errorf("columns = %#v, want %#v", patch.Columns, tt.wantColumns)
+			}
+			if tt.check != nil {
+				tt.check(t, book, patch)
+			}
+		})
+	}
+}
+
+// testPatchBook is the stored book the patch tests apply their documents to
+func testPatchBook(publisherID uuid.UUID, published time.Time, threshold int) *domain.Book {
+	return &domain.Book{
+		ISBN:             "9780134190440",
+		ISBN10:           "0134190440",
+		Title:            "The Go Programming Language",
+		Description:      "A description",
+		PublisherID:      &publisherID,
+		PublicationDate:  &published,
+		Language:         "en",
+		Pages:            320,
+		Format:           "paperback",
+		Price:            39.99,
+		ReorderThreshold: &threshold,
+		Metadata:         `{"series":"Addison-Wesley","tags":{"old":true}}`,
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
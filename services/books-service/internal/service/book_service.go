@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
 	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"github.com/youngermaster/bookstore/services/books-service/pkg/isbn"
 	"gorm.io/gorm"
 )
 
@@ -15,27 +19,103 @@ var (
 	ErrBookNotFound      = errors.New("book not found")
 	ErrBookAlreadyExists = errors.New("book with this ISBN already exists")
 	ErrInvalidInput      = errors.New("invalid input")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidSort       = errors.New("invalid sort")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidReason     = errors.New("invalid stock movement reason")
+	ErrVersionConflict   = errors.New("book was modified by another request")
 )
 
+// maxBatchSize caps how many books can be fetched in one batch lookup
+const maxBatchSize = 100
+
+// maxPriceBuckets caps how many price bucket bounds a facet request may set
+const maxPriceBuckets = 20
+
+// defaultPriceBounds are the price facet bucket upper bounds used when the
+// caller doesn't supply any
+var defaultPriceBounds = []float64{10, 25, 50, 100}
+
+// maxISBNFilter caps how many ISBNs a single listing may filter on
+const maxISBNFilter = 100
+
+// bookSortDefaults lists the sortable fields with whether each sorts
+// descending when no order is given
+var bookSortDefaults = map[string]bool{
+	domain.BookSortCreatedAt:       true,
+	domain.BookSortPrice:           false,
+	domain.BookSortTitle:           false,
+	domain.BookSortPublicationDate: true,
+	domain.BookSortRelevance:       true,
+}
+
+// ParseBookSort validates a sort field and order (asc or desc). An empty
+// field means the default order, which is decided when the listing runs.
+func ParseBookSort(field, order string) (*domain.BookSort, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	order = strings.ToLower(strings.TrimSpace(order))
+	if field == "" {
+		if order != "" {
+			return nil, fmt.Errorf("%w: order requires a sort field", ErrInvalidSort)
+		}
+		return nil, nil
+	}
+
+	descending, ok := bookSortDefaults[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidSort, field)
+	}
+
+	switch order {
+	case "":
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidSort)
+	}
+
+	return &domain.BookSort{Field: field, Descending: descending}, nil
+}
+
 // BookService defines the interface for book business logic
 type BookService interface {
 	CreateBook(ctx context.Context, book *domain.Book) error
 	GetBook(ctx context.Context, id uuid.UUID) (*domain.Book, error)
 	GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error)
-	ListBooks(ctx context.Context, limit, offset int, filters map[string]interface{}) ([]domain.Book, int64, error)
-	UpdateBook(ctx context.Context, book *domain.Book) error
+	GetBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Book, error)
+	GetBookAvailability(ctx context.Context, id uuid.UUID) ([]domain.WarehouseStock, error)
+	ListBooks(ctx context.Context, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error)
+	GetBookFacets(ctx context.Context, filters map[string]interface{}, facets []string, priceBounds []float64) (*domain.Facets, error)
+	UpdateBook(ctx context.Context, book *domain.Book, stock *int, thresholdSet bool) error
+	PatchBook(ctx context.Context, id uuid.UUID, version int, document []byte) (*domain.Book, error)
 	DeleteBook(ctx context.Context, id uuid.UUID) error
-	UpdateBookStock(ctx context.Context, id uuid.UUID, quantity int) error
+	UpdateBookStock(ctx context.Context, movement *domain.StockMovement) error
 }
 
 type bookService struct {
-	bookRepo repository.BookRepository
+	bookRepo      repository.BookRepository
+	categoryRepo  repository.CategoryRepository
+	authorRepo    repository.AuthorRepository
+	publisherRepo repository.PublisherRepository
+	warehouseRepo repository.WarehouseRepository
 }
 
 // NewBookService creates a new instance of BookService
-func NewBookService(bookRepo repository.BookRepository) BookService {
+func NewBookService(
+	bookRepo repository.BookRepository,
+	categoryRepo repository.CategoryRepository,
+	authorRepo repository.AuthorRepository,
+	publisherRepo repository.PublisherRepository,
+	warehouseRepo repository.WarehouseRepository,
+) BookService {
 	return &bookService{
-		bookRepo: bookRepo,
+		bookRepo:      bookRepo,
+		categoryRepo:  categoryRepo,
+		authorRepo:    authorRepo,
+		publisherRepo: publisherRepo,
+		warehouseRepo: warehouseRepo,
 	}
 }
 
@@ -43,10 +123,8 @@ func (s *bookService) CreateBook(ctx context.Context, book *domain.Book) error {
 	if book == nil {
 		return ErrInvalidInput
 	}
-
-	// Validate required fields
-	if book.ISBN == "" || book.Title == "" || book.Price < 0 {
-		return ErrInvalidInput
+	if err := validateNewBook(book); err != nil {
+		return err
 	}
 
 	// Check if book with same ISBN already exists
@@ -77,8 +155,15 @@ func (s *bookService) GetBook(ctx context.Context, id uuid.UUID) (*domain.Book,
 	return book, nil
 }
 
-func (s *bookService) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
-	book, err := s.bookRepo.FindByISBN(ctx, isbn)
+// GetBookByISBN looks a book up by its ISBN-13 or ISBN-10, with or without
+// hyphens
+func (s *bookService) GetBookByISBN(ctx context.Context, value string) (*domain.Book, error) {
+	isbn13, _, err := canonicalISBN(value)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookRepo.FindByISBN(ctx, isbn13)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrBookNotFound
@@ -88,25 +173,166 @@ func (s *bookService) GetBookByISBN(ctx context.Context, isbn string) (*domain.B
 	return book, nil
 }
 
-func (s *bookService) ListBooks(ctx context.Context, limit, offset int, filters map[string]interface{}) ([]domain.Book, int64, error) {
-	// Set default pagination
-	if limit <= 0 || limit > 100 {
-		limit = 20
+// GetBookAvailability returns the book's stock at each warehouse. The levels
+// add up to the book's StockQuantity.
+func (s *bookService) GetBookAvailability(ctx context.Context, id uuid.UUID) ([]domain.WarehouseStock, error) {
+	if _, err := s.GetBook(ctx, id); err != nil {
+		return nil, err
 	}
-	if offset < 0 {
+
+	levels, err := s.warehouseRepo.FindStockByBookID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability: %w", err)
+	}
+	return levels, nil
+}
+
+// GetBooksByIDs returns the books that exist among ids, in the order requested.
+// Unknown IDs are skipped rather than reported as an error.
+func (s *bookService) GetBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Book, error) {
+	if len(ids) > maxBatchSize {
+		return nil, ErrInvalidInput
+	}
+	if len(ids) == 0 {
+		return []domain.Book{}, nil
+	}
+
+	found, err := s.bookRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]domain.Book, len(found))
+	for _, book := range found {
+		byID[book.ID] = book
+	}
+
+	books := make([]domain.Book, 0, len(found))
+	for _, id := range ids {
+		if book, ok := byID[id]; ok {
+			books = append(books, book)
+			delete(byID, id)
+		}
+	}
+	return books, nil
+}
+
+// ListBooks returns one page of books by offset or by cursor. Every page
+// carries cursors to its neighbours; the total is only counted on request.
+func (s *bookService) ListBooks(ctx context.Context, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error) {
+	limit, offset := normalizePagination(page.Limit, page.Offset)
+
+	filters, err := s.resolveFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cursor pins the sort it was issued for
+	var keyset *domain.Keyset
+	if page.Cursor != "" {
+		keyset, err = decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if order, ok := filters["sort"].(domain.BookSort); ok && order != keyset.Sort {
+			return nil, fmt.Errorf("%w: issued for a different sort", ErrInvalidCursor)
+		}
+		filters["sort"] = keyset.Sort
 		offset = 0
 	}
 
-	books, total, err := s.bookRepo.FindAll(ctx, limit, offset, filters)
+	// Search results default to relevance, everything else to newest first
+	_, searching := filters["q"]
+	switch order, ok := filters["sort"].(domain.BookSort); {
+	case !ok && searching:
+		filters["sort"] = domain.BookSort{Field: domain.BookSortRelevance, Descending: true}
+	case !ok:
+		filters["sort"] = domain.BookSort{Field: domain.BookSortCreatedAt, Descending: true}
+	case order.Field == domain.BookSortRelevance && !searching:
+		return nil, fmt.Errorf("%w: relevance requires a search query", ErrInvalidSort)
+	}
+	order := filters["sort"].(domain.BookSort)
+
+	// One extra row tells whether there is another page in the direction of travel
+	books, err := s.bookRepo.FindAll(ctx, limit+1, offset, keyset, filters)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list books: %w", err)
+		return nil, fmt.Errorf("failed to list books: %w", err)
 	}
 
-	return books, total, nil
+	backwards := keyset != nil && keyset.Before
+	more := len(books) > limit
+	if more && backwards {
+		books = books[1:]
+	} else if more {
+		books = books[:limit]
+	}
+
+	result := &domain.BookPage{Books: books, Limit: limit, Offset: offset}
+	if len(books) > 0 {
+		if more || backwards {
+			result.NextCursor = encodeCursor(bookKeyset(books[len(books)-1], order, false))
+		}
+		if (more && backwards) || (!backwards && (keyset != nil || offset > 0)) {
+			result.PrevCursor = encodeCursor(bookKeyset(books[0], order, true))
+		}
+	}
+
+	if page.IncludeTotal {
+		total, err := s.bookRepo.Count(ctx, withoutSort(filters))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count books: %w", err)
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// GetBookFacets counts the books matching filters per value of each requested
+// facet. Unknown facet names are ignored; priceBounds must be positive.
+func (s *bookService) GetBookFacets(ctx context.Context, filters map[string]interface{}, facets []string, priceBounds []float64) (*domain.Facets, error) {
+	requested := make(map[string]bool, len(facets))
+	for _, facet := range facets {
+		requested[strings.ToLower(strings.TrimSpace(facet))] = true
+	}
+	selected := make([]string, 0, len(domain.AllFacets))
+	for _, facet := range domain.AllFacets {
+		if requested[facet] || requested["all"] {
+			selected = append(selected, facet)
+		}
+	}
+	if len(selected) == 0 {
+		return &domain.Facets{}, nil
+	}
+
+	bounds, err := normalizePriceBounds(priceBounds)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err = s.resolveFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.bookRepo.Facets(ctx, withoutSort(filters), selected, bounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get facets: %w", err)
+	}
+	return result, nil
 }
 
-func (s *bookService) UpdateBook(ctx context.Context, book *domain.Book) error {
-	if book == nil || book.ID == uuid.Nil {
+// UpdateBook saves the book if it hasn't changed since the client read
+// book.Version. A zero version means the client sent none, and the update is
+// checked against the version read here. stock is the stock_quantity the
+// client sent, if any; it may only repeat the stored stock. The reorder
+// threshold is only replaced when thresholdSet; otherwise the stored one is
+// kept.
+func (s *bookService) UpdateBook(ctx context.Context, book *domain.Book, stock *int, thresholdSet bool) error {
+	if book == nil || book.ID == uuid.Nil || book.Version < 0 {
+		return ErrInvalidInput
+	}
+	if book.ReorderThreshold != nil && *book.ReorderThreshold < 0 {
 		return ErrInvalidInput
 	}
 
@@ -119,19 +345,127 @@ func (s *bookService) UpdateBook(ctx context.Context, book *domain.Book) error {
 		return fmt.Errorf("failed to check existing book: %w", err)
 	}
 
-	// If ISBN is being changed, check it's not already used
-	if existing.ISBN != book.ISBN {
-		existingISBN, err := s.bookRepo.FindByISBN(ctx, book.ISBN)
+	isbn13, isbn10, err := canonicalISBN(book.ISBN)
+	if err != nil {
+		return err
+	}
+	book.ISBN, book.ISBN10 = isbn13, isbn10
+
+	// Check the new ISBN is free and everything the book links to exists
+	links := &domain.BookPatch{Columns: map[string]interface{}{"publisher_id": book.PublisherID}}
+	for _, author := range book.Authors {
+		links.AuthorIDs = append(links.AuthorIDs, author.ID)
+	}
+	for _, category := range book.Categories {
+		links.CategoryIDs = append(links.CategoryIDs, category.ID)
+	}
+	if err := s.checkBookReferences(ctx, book, existing.ISBN, links); err != nil {
+		return err
+	}
+
+	// Stock only changes through UpdateBookStock so the ledger stays complete
+	if stock != nil && *stock != existing.StockQuantity {
+		return fmt.Errorf("%w: stock_quantity can't be updated here, use PATCH /api/v1/books/%s/stock", ErrInvalidInput, book.ID)
+	}
+	book.StockQuantity = existing.StockQuantity
+	if !thresholdSet {
+		book.ReorderThreshold = existing.ReorderThreshold
+	}
+	book.CreatedAt = existing.CreatedAt
+	if book.Version == 0 {
+		book.Version = existing.Version
+	}
+
+	applied, err := s.bookRepo.Update(ctx, book)
+	if err != nil {
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+	if !applied {
+		return ErrVersionConflict
+	}
+
+	return nil
+}
+
+// PatchBook applies a JSON merge patch to the book if it hasn't changed since
+// the client read version, and returns the updated book. A zero version
+// behaves as in UpdateBook.
+func (s *bookService) PatchBook(ctx context.Context, id uuid.UUID, version int, document []byte) (*domain.Book, error) {
+	if id == uuid.Nil || version < 0 {
+		return nil, ErrInvalidInput
+	}
+
+	book, err := s.bookRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, fmt.Errorf("failed to check existing book: %w", err)
+	}
+	currentISBN := book.ISBN
+
+	patch, err := decodeBookPatch(book, document)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBookReferences(ctx, book, currentISBN, patch); err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = book.Version
+	}
+	applied, err := s.bookRepo.Patch(ctx, id, version, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch book: %w", err)
+	}
+	if !applied {
+		return nil, ErrVersionConflict
+	}
+
+	return s.GetBook(ctx, id)
+}
+
+// checkBookReferences makes sure an updated book's ISBN is still unique and
+// that the publisher, authors and categories it links to exist
+func (s *bookService) checkBookReferences(ctx context.Context, book *domain.Book, currentISBN string, patch *domain.BookPatch) error {
+	if book.ISBN != currentISBN {
+		existing, err := s.bookRepo.FindByISBN(ctx, book.ISBN)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("failed to check ISBN: %w", err)
 		}
-		if existingISBN != nil {
+		if existing != nil {
 			return ErrBookAlreadyExists
 		}
 	}
 
-	if err := s.bookRepo.Update(ctx, book); err != nil {
-		return fmt.Errorf("failed to update book: %w", err)
+	if _, ok := patch.Columns["publisher_id"]; ok && book.PublisherID != nil {
+		if _, err := s.publisherRepo.FindByID(ctx, *book.PublisherID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: publisher %s not found", ErrInvalidInput, *book.PublisherID)
+			}
+			return fmt.Errorf("failed to check publisher: %w", err)
+		}
+	}
+
+	if len(patch.AuthorIDs) > 0 {
+		authors, err := s.authorRepo.FindByIDs(ctx, patch.AuthorIDs)
+		if err != nil {
+			return fmt.Errorf("failed to check authors: %w", err)
+		}
+		if len(authors) != len(patch.AuthorIDs) {
+			return fmt.Errorf("%w: author_ids lists an unknown author", ErrInvalidInput)
+		}
+	}
+
+	if len(patch.CategoryIDs) > 0 {
+		categories, err := s.categoryRepo.FindByIDs(ctx, patch.CategoryIDs)
+		if err != nil {
+			return fmt.Errorf("failed to check categories: %w", err)
+		}
+		if len(categories) != len(patch.CategoryIDs) {
+			return fmt.Errorf("%w: category_ids lists an unknown category", ErrInvalidInput)
+		}
 	}
 
 	return nil
@@ -154,24 +488,177 @@ func (s *bookService) DeleteBook(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (s *bookService) UpdateBookStock(ctx context.Context, id uuid.UUID, quantity int) error {
+// manualStockReasons are the reasons a stock change can be recorded with
+// directly; reservation movements come from the reservation system
+var manualStockReasons = map[string]bool{
+	domain.StockReasonRestock:    true,
+	domain.StockReasonSale:       true,
+	domain.StockReasonReturn:     true,
+	domain.StockReasonAdjustment: true,
+}
+
+func (s *bookService) UpdateBookStock(ctx context.Context, movement *domain.StockMovement) error {
+	if movement == nil || movement.BookID == uuid.Nil || movement.Delta == 0 {
+		return ErrInvalidInput
+	}
+	if movement.WarehouseID == nil {
+		return fmt.Errorf("%w: warehouse_id is required", ErrInvalidInput)
+	}
+	if !manualStockReasons[movement.Reason] {
+		return ErrInvalidReason
+	}
+
+	if _, err := s.warehouseRepo.FindByID(ctx, *movement.WarehouseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWarehouseNotFound
+		}
+		return fmt.Errorf("failed to check warehouse: %w", err)
+	}
+
 	// Check if book exists
-	book, err := s.bookRepo.FindByID(ctx, id)
-	if err != nil {
+	if _, err := s.bookRepo.FindByID(ctx, movement.BookID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrBookNotFound
 		}
 		return fmt.Errorf("failed to check existing book: %w", err)
 	}
 
-	// Validate stock won't go negative
-	if book.StockQuantity+quantity < 0 {
-		return errors.New("insufficient stock")
+	// The repository only applies the delta if the warehouse's stock stays
+	// non-negative
+	applied, err := s.bookRepo.UpdateStock(ctx, movement)
+	if err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+	if !applied {
+		return ErrInsufficientStock
 	}
 
-	if err := s.bookRepo.UpdateStock(ctx, id, quantity); err != nil {
-		return fmt.Errorf("failed to update stock: %w", err)
+	return nil
+}
+
+// validateNewBook checks the fields a book needs before it is created and
+// stores its ISBN in canonical form
+func validateNewBook(book *domain.Book) error {
+	if book.ISBN == "" || book.Title == "" || book.Price < 0 || book.StockQuantity < 0 {
+		return ErrInvalidInput
+	}
+	if book.ReorderThreshold != nil && *book.ReorderThreshold < 0 {
+		return ErrInvalidInput
 	}
 
+	isbn13, isbn10, err := canonicalISBN(book.ISBN)
+	if err != nil {
+		return err
+	}
+	book.ISBN, book.ISBN10 = isbn13, isbn10
 	return nil
 }
+
+// canonicalISBN validates an ISBN in any accepted form and returns its
+// ISBN-13, and its ISBN-10 when it has one
+func canonicalISBN(value string) (string, string, error) {
+	isbn13, err := isbn.Normalize(value)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %q is not a valid ISBN-10 or ISBN-13", ErrInvalidInput, value)
+	}
+	isbn10, _ := isbn.ToISBN10(isbn13)
+	return isbn13, isbn10, nil
+}
+
+// resolveFilters validates list filters and returns a copy in which
+// category_id is expanded to the category and all of its descendants
+func (s *bookService) resolveFilters(ctx context.Context, filters map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		resolved[key] = value
+	}
+
+	if isbns, ok := resolved["isbn"].([]string); ok {
+		if len(isbns) > maxISBNFilter {
+			return nil, ErrInvalidInput
+		}
+		normalized := make([]string, len(isbns))
+		for i, value := range isbns {
+			isbn13, _, err := canonicalISBN(value)
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = isbn13
+		}
+		resolved["isbn"] = normalized
+	}
+
+	minPrice, hasMin := resolved["min_price"].(float64)
+	maxPrice, hasMax := resolved["max_price"].(float64)
+	if hasMin && hasMax && minPrice > maxPrice {
+		return nil, ErrInvalidInput
+	}
+
+	from, hasFrom := resolved["published_from"].(time.Time)
+	to, hasTo := resolved["published_to"].(time.Time)
+	if hasFrom && hasTo && from.After(to) {
+		return nil, ErrInvalidInput
+	}
+
+	if categoryID, ok := resolved["category_id"].(uuid.UUID); ok {
+		descendants, err := s.categoryRepo.FindDescendantIDs(ctx, categoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve category filter: %w", err)
+		}
+
+		// Sorted so equal filters produce equal cache keys
+		ids := append([]uuid.UUID{categoryID}, descendants...)
+		sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+		resolved["category_id"] = ids
+	}
+
+	return resolved, nil
+}
+
+// withoutSort copies filters minus the sort, for queries whose result doesn't
+// depend on order
+func withoutSort(filters map[string]interface{}) map[string]interface{} {
+	unsorted := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "sort" {
+			unsorted[key] = value
+		}
+	}
+	return unsorted
+}
+
+// normalizePriceBounds sorts and deduplicates price bucket bounds, falling
+// back to defaultPriceBounds when none are given
+func normalizePriceBounds(bounds []float64) ([]float64, error) {
+	if len(bounds) == 0 {
+		return defaultPriceBounds, nil
+	}
+	if len(bounds) > maxPriceBuckets {
+		return nil, ErrInvalidInput
+	}
+
+	sorted := append([]float64(nil), bounds...)
+	sort.Float64s(sorted)
+
+	normalized := make([]float64, 0, len(sorted))
+	for _, bound := range sorted {
+		if bound <= 0 {
+			return nil, ErrInvalidInput
+		}
+		if len(normalized) == 0 || normalized[len(normalized)-1] != bound {
+			normalized = append(normalized, bound)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizePagination applies the default page size and clamps out-of-range values
+func normalizePagination(limit, offset int) (int, int) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryAlreadyExists = errors.New("category with this slug already exists")
+	ErrCategoryHasChildren   = errors.New("category has child categories")
+	ErrCategoryHasBooks      = errors.New("category has books assigned")
+	ErrInvalidParent         = errors.New("invalid parent category")
+)
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CategoryService defines the interface for category business logic
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	ListCategories(ctx context.Context) ([]domain.Category, error)
+	GetCategoryTree(ctx context.Context) ([]domain.Category, error)
+	UpdateCategory(ctx context.Context, category *domain.Category, thresholdSet bool) error
+	DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+// NewCategoryService creates a new instance of CategoryService
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if category == nil {
+		return ErrInvalidInput
+	}
+
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return ErrInvalidInput
+	}
+	if category.Slug == "" {
+		category.Slug = category.Name
+	}
+	category.Slug = slugify(category.Slug)
+	if category.Slug == "" {
+		return ErrInvalidInput
+	}
+	if category.ReorderThreshold != nil && *category.ReorderThreshold < 0 {
+		return ErrInvalidInput
+	}
+
+	// Check slug uniqueness
+	existing, err := s.categoryRepo.FindBySlug(ctx, category.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check existing category: %w", err)
+	}
+	if existing != nil {
+		return ErrCategoryAlreadyExists
+	}
+
+	if category.ParentID != nil {
+		if _, err := s.GetCategory(ctx, *category.ParentID); err != nil {
+			if errors.Is(err, ErrCategoryNotFound) {
+				return ErrInvalidParent
+			}
+			return err
+		}
+	}
+
+	// Associations are managed through ParentID only
+	category.Parent = nil
+	category.Children = nil
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return nil
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	category, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+func (s *categoryService) GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	category, err := s.categoryRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category by slug: %w", err)
+	}
+	return category, nil
+}
+
+func (s *categoryService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]domain.Category, error) {
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// UpdateCategory replaces the category. Its reorder threshold is only
+// replaced when thresholdSet; otherwise the stored one is kept.
+func (s *categoryService) UpdateCategory(ctx context.Context, category *domain.Category, thresholdSet bool) error {
+	if category == nil || category.ID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Slug == "" {
+		category.Slug = category.Name
+	}
+	category.Slug = slugify(category.Slug)
+	if category.Name == "" || category.Slug == "" {
+		return ErrInvalidInput
+	}
+	if category.ReorderThreshold != nil && *category.ReorderThreshold < 0 {
+		return ErrInvalidInput
+	}
+
+	existing, err := s.GetCategory(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+
+	// If slug is being changed, check it's not already used
+	if existing.Slug != category.Slug {
+		existingSlug, err := s.categoryRepo.FindBySlug(ctx, category.Slug)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check slug: %w", err)
+		}
+		if existingSlug != nil {
+			return ErrCategoryAlreadyExists
+		}
+	}
+
+	// A category can't be moved below itself or one of its descendants
+	if category.ParentID != nil {
+		if *category.ParentID == category.ID {
+			return ErrInvalidParent
+		}
+		if _, err := s.GetCategory(ctx, *category.ParentID); err != nil {
+			if errors.Is(err, ErrCategoryNotFound) {
+				return ErrInvalidParent
+			}
+			return err
+		}
+		descendants, err := s.categoryRepo.FindDescendantIDs(ctx, category.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load descendants: %w", err)
+		}
+		for _, id := range descendants {
+			if id == *category.ParentID {
+				return ErrInvalidParent
+			}
+		}
+	}
+
+	if !thresholdSet {
+		category.ReorderThreshold = existing.ReorderThreshold
+	}
+	category.CreatedAt = existing.CreatedAt
+	category.Parent = nil
+	category.Children = nil
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return nil
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID, cascade bool) error {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return err
+	}
+
+	descendants, err := s.categoryRepo.FindDescendantIDs(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load descendants: %w", err)
+	}
+	ids := append([]uuid.UUID{id}, descendants...)
+
+	if !cascade {
+		if len(descendants) > 0 {
+			return ErrCategoryHasChildren
+		}
+		bookCount, err := s.categoryRepo.CountBooks(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to count books: %w", err)
+		}
+		if bookCount > 0 {
+			return ErrCategoryHasBooks
+		}
+	}
+
+	if err := s.categoryRepo.DeleteTree(ctx, ids); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return nil
+}
+
+// buildCategoryTree nests a flat category list into root categories with
+// their Children populated recursively
+func buildCategoryTree(categories []domain.Category) []domain.Category {
+	byParent := make(map[uuid.UUID][]domain.Category)
+	roots := make([]domain.Category, 0)
+	for _, category := range categories {
+		category.Parent = nil
+		category.Children = nil
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		byParent[*category.ParentID] = append(byParent[*category.ParentID], category)
+	}
+
+	var attach func(nodes []domain.Category) []domain.Category
+	attach = func(nodes []domain.Category) []domain.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(byParent[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	return attach(roots)
+}
+
+// slugify lowercases a string and replaces runs of non-alphanumeric
+// characters with a single dash
+func slugify(value string) string {
+	slug := slugInvalidChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(value)), "-")
+	return strings.Trim(slug, "-")
+}
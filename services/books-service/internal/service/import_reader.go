@@ -0,0 +1,237 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+// maxImportLineSize caps the length of a single NDJSON line
+const maxImportLineSize = 1 << 20
+
+// importListSeparator separates the authors and categories in a CSV cell
+const importListSeparator = "|"
+
+// importColumns are the CSV header names an import understands. They match
+// the NDJSON keys.
+var importColumns = map[string]bool{
+	"isbn":              true,
+	"title":             true,
+	"description":       true,
+	"publisher":         true,
+	"publication_date":  true,
+	"language":          true,
+	"pages":             true,
+	"format":            true,
+	"price":             true,
+	"stock_quantity":    true,
+	"reorder_threshold": true,
+	"cover_image_url":   true,
+	"authors":           true,
+	"categories":        true,
+}
+
+// importReader reads catalog rows one at a time. Next returns io.EOF after the
+// last row. An error with a row is about that row only and reading can go on;
+// an error without one means the stream can't be read any further.
+type importReader interface {
+	Next() (*domain.BookImportRow, error)
+}
+
+// newImportReader reads r in the given format, checking the CSV header or the
+// ONIX message up front. ONIX prices are taken in the given currency.
+func newImportReader(format string, r io.Reader, currency string) (importReader, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return newCSVImportReader(r)
+	case domain.ImportFormatNDJSON:
+		return newNDJSONImportReader(r), nil
+	case domain.ImportFormatONIX:
+		return newONIXImportReader(r, currency)
+	}
+	return nil, fmt.Errorf("%w: format must be %s, %s or %s", ErrInvalidInput,
+		domain.ImportFormatCSV, domain.ImportFormatNDJSON, domain.ImportFormatONIX)
+}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCSVImportReader reads the header row, which must name an isbn column and
+// may list the other columns in any order
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: CSV has no header row", ErrInvalidInput)
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, fmt.Errorf("%w: invalid CSV header: %v", ErrInvalidInput, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !importColumns[name] {
+			return nil, fmt.Errorf("%w: unknown CSV column %q", ErrInvalidInput, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: CSV column %q appears twice", ErrInvalidInput, name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	if !seen["isbn"] {
+		return nil, fmt.Errorf("%w: CSV header has no isbn column", ErrInvalidInput)
+	}
+
+	reader.FieldsPerRecord = len(columns)
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) Next() (*domain.BookImportRow, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &domain.BookImportRow{Line: parseErr.StartLine}, parseErr.Err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := &domain.BookImportRow{Line: line}
+	for i, value := range record {
+		if err := setImportField(row, r.columns[i], strings.TrimSpace(value)); err != nil {
+			return row, err
+		}
+	}
+	return row, nil
+}
+
+// setImportField parses one CSV cell into the row. Empty cells are left out.
+func setImportField(row *domain.BookImportRow, column, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+	switch column {
+	case "isbn":
+		row.ISBN = value
+	case "title":
+		row.Title = &value
+	case "description":
+		row.Description = &value
+	case "publisher":
+		row.Publisher = &value
+	case "publication_date":
+		row.PublicationDate = &value
+	case "language":
+		row.Language = &value
+	case "format":
+		row.Format = &value
+	case "cover_image_url":
+		row.CoverImageURL = &value
+	case "pages":
+		row.Pages, err = parseImportInt(value)
+	case "stock_quantity":
+		row.StockQuantity, err = parseImportInt(value)
+	case "reorder_threshold":
+		row.ReorderThreshold, err = parseImportInt(value)
+	case "price":
+		var price float64
+		price, err = strconv.ParseFloat(value, 64)
+		row.Price = &price
+	case "authors":
+		row.Authors = splitImportList(value)
+	case "categories":
+		row.Categories = splitImportList(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s must be a number", ErrInvalidInput, column)
+	}
+	return nil
+}
+
+func parseImportInt(value string) (*int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
+
+// splitImportList splits a CSV cell into its non-empty names
+func splitImportList(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, importListSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONImportReader(r io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+// Next decodes the next non-blank line as a JSON object with the same keys as
+// the CSV columns
+func (r *ndjsonImportReader) Next() (*domain.BookImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var row domain.BookImportRow
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return &domain.BookImportRow{Line: r.line}, fmt.Errorf("%w: invalid JSON: %v", ErrInvalidInput, err)
+		}
+		row.Line = r.line
+		return &row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidInput, r.line+1, maxImportLineSize)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+func TestNewImportReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		wantErr bool
+	}{
+		{name: "csv header", format: domain.ImportFormatCSV, input: "isbn,title,price\n"},
+		{name: "csv header in any case and order", format: domain.ImportFormatCSV, input: " Title , ISBN\n"},
+		{name: "csv header after a byte order mark", format: domain.ImportFormatCSV, input: "\ufeffisbn,title\n"},
+		{name: "csv without a header", format: domain.ImportFormatCSV, input: "", wantErr: true},
+		{name: "csv header without isbn", format: domain.ImportFormatCSV, input: "title,price\n", wantErr: true},
+		{name: "csv header with an unknown column", format: domain.ImportFormatCSV, input: "isbn,rating\n", wantErr: true},
+		{name: "csv header with a repeated column", format: domain.ImportFormatCSV, input: "isbn,title,TITLE\n", wantErr: true},
+		{name: "csv header with an open quote", format: domain.ImportFormatCSV, input: "isbn,\"title\n", wantErr: true},
+		{name: "csv byte order mark after the first column", format: domain.ImportFormatCSV, input: "isbn,\ufefftitle\n", wantErr: true},
+		{name: "ndjson has no header", format: domain.ImportFormatNDJSON, input: ""},
+		{name: "unknown format", format: "xlsx", input: "isbn\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newImportReader(tt.format, strings.NewReader(tt.input), "USD")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("newImportReader(%q) error = %v, want ErrInvalidInput", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newImportReader(%q) error = %v", tt.input, err)
+			}
+		})
+	}
+}
+
+// importResult is what a test saw of one row
+type importResult struct {
+	line    int
+	isbn    string
+	invalid bool
+}
+
+func TestImportReaderRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []importResult
+	}{
+		{
+			name:   "csv lines count the header and skip blank lines",
+			format: domain.ImportFormatCSV,
+			input:  "isbn,title\n9780306406157,A\n\n9780134190440,B\n",
+			want:   []importResult{{line: 2, isbn: "9780306406157"}, {line: 4, isbn: "9780134190440"}},
+		},
+		{
+			name:   "csv row starts on its first line",
+			format: domain.ImportFormatCSV,
+			input:  "isbn,title\n9780306406157,\"A\nlong title\"\n9780134190440,B",
+			want:   []importResult{{line: 2, isbn: "9780306406157"}, {line: 4, isbn: "9780134190440"}},
+		},
+		{
+			name:   "csv number that isn't one fails its row only",
+			format: domain.ImportFormatCSV,
+			input:  "isbn,pages\n9780306406157,ten\n9780134190440,12\n",
+			want:   []importResult{{line: 2, isbn: "9780306406157", invalid: true}, {line: 3, isbn: "9780134190440"}},
+		},
+		{
+			name:   "csv row with too few cells fails its row only",
+			format: domain.ImportFormatCSV,
+			input:  "isbn,title\n9780306406157\n9780134190440,B\n",
+			want:   []importResult{{line: 2, invalid: true}, {line: 3, isbn: "9780134190440"}},
+		},
+		{
+			name:   "csv header only",
+			format: domain.ImportFormatCSV,
+			input:  "isbn,title\n",
+		},
+		{
+			name:   "ndjson lines count blank lines",
+			format: domain.ImportFormatNDJSON,
+			input:  "{\"isbn\":\"9780306406157\"}\n\n  \r\n{\"isbn\":\"9780134190440\"}",
+			want:   []importResult{{line: 1, isbn: "9780306406157"}, {line: 4, isbn: "9780134190440"}},
+		},
+		{
+			name:   "ndjson malformed line fails its row only",
+			format: domain.ImportFormatNDJSON,
+			input:  "{\"isbn\":\"9780306406157\"}\n{\"isbn\":\n{\"isbn\":\"9780134190440\"}\n",
+			want: []importResult{
+				{line: 1, isbn: "9780306406157"},
+				{line: 2, invalid: true},
+				{line: 3, isbn: "9780134190440"},
+			},
+		},
+		{
+			name:   "ndjson unknown key fails its row",
+			format: domain.ImportFormatNDJSON,
+			input:  "{\"isbn\":\"9780306406157\",\"rating\":5}\n",
+			want:   []importResult{{line: 1, invalid: true}},
+		},
+		{
+			name:   "ndjson value of the wrong type fails its row",
+			format: domain.ImportFormatNDJSON,
+			input:  "{\"isbn\":\"9780306406157\",\"pages\":\"ten\"}\n",
+			want:   []importResult{{line: 1, invalid: true}},
+		},
+		{
+			name:   "ndjson array fails its row",
+			format: domain.ImportFormatNDJSON,
+			input:  "[\"9780306406157\"]\n",
+			want:   []importResult{{line: 1, invalid: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := newImportReader(tt.format, strings.NewReader(tt.input), "USD")
+			if err != nil {
+				t.Fatalf("newImportReader() error = %v", err)
+			}
+
+			var got []importResult
+			for {
+				row, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if row == nil {
+					t.Fatalf("Next() error = %v, want a row", err)
+				}
+				got = append(got, importResult{line: row.Line, isbn: row.ISBN, invalid: err != nil})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVImportReaderFields(t *testing.T) {
+	input := "isbn,title,description,price,pages,authors,categories\n" +
+		"9780306406157, The Title ,,12.50,320, Ann Leckie | | Iain Banks ,|\n"
+	reader, err := newImportReader(domain.ImportFormatCSV, strings.NewReader(input), "USD")
+	if err != nil {
+		t.Fatalf("newImportReader() error = %v", err)
+	}
+	row, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	if row.Title == nil || *row.Title != "The Title" {
+		t.Errorf("title = %v, want trimmed", row.Title)
+	}
+	if row.Description != nil {
+		t.Errorf("description = %q, want an empty cell left out", *row.Description)
+	}
+	if row.Price == nil || *row.Price != 12.5 || row.Pages == nil || *row.Pages != 320 {
+		t.Errorf("price, pages = %v, %v", row.Price, row.Pages)
+	}
+	if !reflect.DeepEqual(row.Authors, []string{"Ann Leckie", "Iain Banks"}) {
+		t.Errorf("authors = %q", row.Authors)
+	}
+	if row.Categories != nil {
+		t.Errorf("categories = %q, want a cell of separators left out", row.Categories)
+	}
+}
+
+func TestNDJSONImportReaderLongLine(t *testing.T) {
+	input := "{\"isbn\":\"9780306406157\"}\n{\"title\":\"" + strings.Repeat("x", maxImportLineSize) + "\"}\n"
+	reader, err := newImportReader(domain.ImportFormatNDJSON, strings.NewReader(input), "USD")
+	if err != nil {
+		t.Fatalf("newImportReader() error = %v", err)
+	}
+	if _, err := reader.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	// A line too long to read stops the import rather than failing a row
+	row, err := reader.Next()
+	if row != nil || !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Next() = %v, %v, want no row and an error about line 2", row, err)
+	}
+}
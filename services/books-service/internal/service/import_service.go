@@ -0,0 +1,591 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImportService defines the interface for bulk catalog imports
+type ImportService interface {
+	ImportBooks(ctx context.Context, format string, r io.Reader, dryRun bool) (*domain.ImportReport, error)
+}
+
+type importService struct {
+	bookService   BookService
+	authorRepo    repository.AuthorRepository
+	publisherRepo repository.PublisherRepository
+	categoryRepo  repository.CategoryRepository
+	recordRepo    repository.RecordReferenceRepository
+	warehouseRepo repository.WarehouseRepository
+	currency      string
+}
+
+// NewImportService creates a new instance of ImportService
+func NewImportService(
+	bookService BookService,
+	authorRepo repository.AuthorRepository,
+	publisherRepo repository.PublisherRepository,
+	categoryRepo repository.CategoryRepository,
+	recordRepo repository.RecordReferenceRepository,
+	warehouseRepo repository.WarehouseRepository,
+	currency string,
+) ImportService {
+	return &importService{
+		bookService:   bookService,
+		authorRepo:    authorRepo,
+		publisherRepo: publisherRepo,
+		categoryRepo:  categoryRepo,
+		recordRepo:    recordRepo,
+		warehouseRepo: warehouseRepo,
+		currency:      currency,
+	}
+}
+
+// ImportBooks reads rows from r one at a time and upserts each by its record
+// reference, if it has one, or else by ISBN. Rows that wouldn't change their
+// book are counted as unchanged and not saved, so importing the same feed
+// twice changes nothing. A row that fails is reported and the import moves on;
+// rows are applied one by one, so earlier rows stay imported, as do the names
+// a failed row created before it failed, which its error lists. Only an
+// unreadable header or stream stops the import, with the report of the rows
+// read so far. A dry run validates every row and reports what would change
+// without writing anything.
+func (s *importService) ImportBooks(ctx context.Context, format string, r io.Reader, dryRun bool) (*domain.ImportReport, error) {
+	reader, err := newImportReader(format, r, s.currency)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &importRun{
+		importService: s,
+		dryRun:        dryRun,
+		report:        &domain.ImportReport{DryRun: dryRun, Errors: []domain.ImportRowError{}},
+		authors:       map[string]uuid.UUID{},
+		publishers:    map[string]uuid.UUID{},
+		categories:    map[string]uuid.UUID{},
+		planned:       map[string]bool{},
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return run.report, err
+		}
+
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return run.report, nil
+		}
+		if row == nil {
+			return run.report, fmt.Errorf("failed to read import: %w", err)
+		}
+
+		run.report.Rows++
+		run.rowCreated = nil
+		if err == nil {
+			err = run.importRow(ctx, row)
+		}
+		if err != nil {
+			run.report.Failed++
+			run.report.Errors = append(run.report.Errors, domain.ImportRowError{
+				Line:    row.Line,
+				ISBN:    row.ISBN,
+				Error:   err.Error(),
+				Created: run.rowCreated,
+			})
+		}
+	}
+}
+
+// importRun is the state of a single import. Names are resolved once per run.
+type importRun struct {
+	*importService
+	dryRun bool
+	report *domain.ImportReport
+
+	authors    map[string]uuid.UUID
+	publishers map[string]uuid.UUID
+	categories map[string]uuid.UUID
+
+	// ISBNs a dry run would have created, so repeats count as updates
+	planned map[string]bool
+
+	// the names the current row created, reported if the row then fails
+	rowCreated []string
+
+	// the default warehouse, which takes stock adjustments, once looked up
+	warehouseID *uuid.UUID
+}
+
+// importLinks are the IDs a row links its book to. Nil fields weren't in the row.
+type importLinks struct {
+	publisherID *uuid.UUID
+	authorIDs   []uuid.UUID
+	categoryIDs []uuid.UUID
+}
+
+func (r *importRun) importRow(ctx context.Context, row *domain.BookImportRow) error {
+	isbn13, _, err := canonicalISBN(row.ISBN)
+	if err != nil {
+		return err
+	}
+	publicationDate, err := parseImportDate(row.PublicationDate)
+	if err != nil {
+		return err
+	}
+
+	existing, referenced, err := r.findBook(ctx, row.RecordReference, isbn13)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil && !r.planned[isbn13] {
+		book, err := r.createBook(ctx, row, isbn13, publicationDate)
+		if err != nil {
+			return err
+		}
+		r.report.Created++
+		return r.saveReference(ctx, row, book)
+	}
+
+	if existing == nil {
+		r.report.Updated++
+		return nil
+	}
+	changed, err := r.updateBook(ctx, row, existing, isbn13, publicationDate)
+	if err != nil {
+		return err
+	}
+	if changed {
+		r.report.Updated++
+	} else {
+		r.report.Unchanged++
+	}
+	if referenced {
+		return nil
+	}
+	return r.saveReference(ctx, row, existing)
+}
+
+// findBook looks the book up by its record reference and then by ISBN, and
+// reports whether the reference found it
+func (r *importRun) findBook(ctx context.Context, reference, isbn13 string) (*domain.Book, bool, error) {
+	if reference != "" {
+		record, err := r.recordRepo.FindByReference(ctx, reference)
+		if err == nil {
+			book, err := r.bookService.GetBook(ctx, record.BookID)
+			return book, true, err
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, fmt.Errorf("failed to look up record %q: %w", reference, err)
+		}
+	}
+
+	book, err := r.bookService.GetBookByISBN(ctx, isbn13)
+	if errors.Is(err, ErrBookNotFound) {
+		return nil, false, nil
+	}
+	return book, false, err
+}
+
+// saveReference remembers which book the row's record reference describes
+func (r *importRun) saveReference(ctx context.Context, row *domain.BookImportRow, book *domain.Book) error {
+	if row.RecordReference == "" || r.dryRun {
+		return nil
+	}
+	if err := r.recordRepo.Save(ctx, row.RecordReference, book.ID); err != nil {
+		return fmt.Errorf("failed to save record %q: %w", row.RecordReference, err)
+	}
+	return nil
+}
+
+func (r *importRun) createBook(ctx context.Context, row *domain.BookImportRow, isbn13 string, publicationDate *time.Time) (*domain.Book, error) {
+	if row.Title == nil {
+		return nil, fmt.Errorf("%w: title is required for new books", ErrInvalidInput)
+	}
+	if row.Price == nil {
+		return nil, fmt.Errorf("%w: price is required for new books", ErrInvalidInput)
+	}
+
+	book := &domain.Book{
+		ISBN:             isbn13,
+		Title:            *row.Title,
+		Price:            *row.Price,
+		PublicationDate:  publicationDate,
+		ReorderThreshold: row.ReorderThreshold,
+	}
+	setImportString(&book.Description, row.Description)
+	setImportString(&book.Language, row.Language)
+	setImportString(&book.Format, row.Format)
+	setImportString(&book.CoverImageURL, row.CoverImageURL)
+	if row.Pages != nil {
+		book.Pages = *row.Pages
+	}
+	if row.StockQuantity != nil {
+		book.StockQuantity = *row.StockQuantity
+	}
+	if err := validateNewBook(book); err != nil {
+		return nil, err
+	}
+
+	links, err := r.resolveLinks(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if r.dryRun {
+		r.planned[isbn13] = true
+		return book, nil
+	}
+
+	book.PublisherID = links.publisherID
+	for _, id := range links.authorIDs {
+		book.Authors = append(book.Authors, domain.Author{ID: id})
+	}
+	for _, id := range links.categoryIDs {
+		book.Categories = append(book.Categories, domain.Category{ID: id})
+	}
+	if err := r.bookService.CreateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// updateBook merge-patches the fields the row has onto the existing book, and
+// reports whether that changes anything. A different stock quantity is
+// recorded as an adjustment movement in the default warehouse once the fields
+// are saved.
+func (r *importRun) updateBook(ctx context.Context, row *domain.BookImportRow, existing *domain.Book, isbn13 string, publicationDate *time.Time) (bool, error) {
+	patch := map[string]interface{}{}
+	for field, value := range map[string]*string{
+		"title":           row.Title,
+		"description":     row.Description,
+		"language":        row.Language,
+		"format":          row.Format,
+		"cover_image_url": row.CoverImageURL,
+	} {
+		if value != nil {
+			patch[field] = *value
+		}
+	}
+	for field, value := range map[string]*int{
+		"pages":             row.Pages,
+		"reorder_threshold": row.ReorderThreshold,
+	} {
+		if value != nil {
+			patch[field] = *value
+		}
+	}
+	if row.Price != nil {
+		patch["price"] = *row.Price
+	}
+	if publicationDate != nil {
+		patch["publication_date"] = publicationDate
+	}
+	// A book found by its record reference takes the record's current ISBN
+	if isbn13 != existing.ISBN {
+		patch["isbn"] = isbn13
+	}
+
+	// Validate the fields before creating anything the row links to
+	document, err := json.Marshal(patch)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	preview := *existing
+	if _, err := decodeBookPatch(&preview, document); err != nil {
+		return false, err
+	}
+
+	links, err := r.resolveLinks(ctx, row)
+	if err != nil {
+		return false, err
+	}
+	stockDelta := 0
+	if row.StockQuantity != nil {
+		if *row.StockQuantity < 0 {
+			return false, fmt.Errorf("%w: stock_quantity can't be negative", ErrInvalidInput)
+		}
+		stockDelta = *row.StockQuantity - existing.StockQuantity
+	}
+	sameFields := sameImportedFields(existing, &preview) && sameImportLinks(existing, links)
+	if sameFields && stockDelta == 0 {
+		return false, nil
+	}
+	if r.dryRun {
+		return true, nil
+	}
+	if sameFields {
+		return true, r.adjustStock(ctx, existing, stockDelta)
+	}
+
+	if links.publisherID != nil {
+		patch["publisher_id"] = links.publisherID
+	}
+	if links.authorIDs != nil {
+		patch["author_ids"] = links.authorIDs
+	}
+	if links.categoryIDs != nil {
+		patch["category_ids"] = links.categoryIDs
+	}
+
+	if document, err = json.Marshal(patch); err != nil {
+		return false, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	if _, err := r.bookService.PatchBook(ctx, existing.ID, existing.Version, document); err != nil {
+		return false, err
+	}
+	if stockDelta != 0 {
+		if err := r.adjustStock(ctx, existing, stockDelta); err != nil {
+			return true, fmt.Errorf("updated the book but not its stock: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// adjustStock records an import's change to a book's stock as an adjustment
+// in the default warehouse
+func (r *importRun) adjustStock(ctx context.Context, book *domain.Book, delta int) error {
+	if r.warehouseID == nil {
+		warehouse, err := r.warehouseRepo.FindByCode(ctx, domain.DefaultWarehouseCode)
+		if err != nil {
+			return fmt.Errorf("failed to find the default warehouse: %w", err)
+		}
+		r.warehouseID = &warehouse.ID
+	}
+
+	return r.bookService.UpdateBookStock(ctx, &domain.StockMovement{
+		BookID:      book.ID,
+		WarehouseID: r.warehouseID,
+		Delta:       delta,
+		Reason:      domain.StockReasonAdjustment,
+		ReferenceID: "import",
+	})
+}
+
+// sameImportedFields reports whether an import leaves the fields it can set
+// as they were
+func sameImportedFields(book, updated *domain.Book) bool {
+	if book.ISBN != updated.ISBN ||
+		book.Title != updated.Title ||
+		book.Description != updated.Description ||
+		book.Language != updated.Language ||
+		book.Format != updated.Format ||
+		book.CoverImageURL != updated.CoverImageURL ||
+		book.Pages != updated.Pages ||
+		book.Price != updated.Price {
+		return false
+	}
+
+	if (book.PublicationDate == nil) != (updated.PublicationDate == nil) ||
+		book.PublicationDate != nil && !book.PublicationDate.Equal(*updated.PublicationDate) {
+		return false
+	}
+	if (book.ReorderThreshold == nil) != (updated.ReorderThreshold == nil) ||
+		book.ReorderThreshold != nil && *book.ReorderThreshold != *updated.ReorderThreshold {
+		return false
+	}
+	return true
+}
+
+// sameImportLinks reports whether the links leave the book's publisher,
+// authors and their order, and categories as they were. A name a dry run
+// would create is always a change.
+func sameImportLinks(book *domain.Book, links *importLinks) bool {
+	if links.publisherID != nil && (book.PublisherID == nil || *book.PublisherID != *links.publisherID) {
+		return false
+	}
+
+	if links.authorIDs != nil {
+		if len(links.authorIDs) != len(book.Authors) {
+			return false
+		}
+		for i, id := range links.authorIDs {
+			if book.Authors[i].ID != id {
+				return false
+			}
+		}
+	}
+
+	if links.categoryIDs != nil {
+		if len(links.categoryIDs) != len(book.Categories) {
+			return false
+		}
+		linked := make(map[uuid.UUID]bool, len(book.Categories))
+		for _, category := range book.Categories {
+			linked[category.ID] = true
+		}
+		for _, id := range links.categoryIDs {
+			if !linked[id] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// resolveLinks finds, or creates, the publisher, authors and categories a row
+// names. In a dry run nothing is created and missing names resolve to uuid.Nil.
+func (r *importRun) resolveLinks(ctx context.Context, row *domain.BookImportRow) (*importLinks, error) {
+	links := &importLinks{}
+
+	if row.Publisher != nil {
+		id, err := r.resolvePublisher(ctx, strings.TrimSpace(*row.Publisher))
+		if err != nil {
+			return nil, err
+		}
+		links.publisherID = &id
+	}
+
+	if row.Authors != nil {
+		links.authorIDs = []uuid.UUID{}
+		for _, name := range row.Authors {
+			id, err := r.resolveAuthor(ctx, strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			links.authorIDs = appendImportLink(links.authorIDs, id)
+		}
+	}
+
+	if row.Categories != nil {
+		links.categoryIDs = []uuid.UUID{}
+		for _, name := range row.Categories {
+			id, err := r.resolveCategory(ctx, strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			links.categoryIDs = appendImportLink(links.categoryIDs, id)
+		}
+	}
+
+	return links, nil
+}
+
+func (r *importRun) resolvePublisher(ctx context.Context, name string) (uuid.UUID, error) {
+	if name == "" {
+		return uuid.Nil, fmt.Errorf("%w: publisher name is empty", ErrInvalidInput)
+	}
+	return r.resolve(r.publishers, strings.ToLower(name), fmt.Sprintf("publisher %q", name), &r.report.PublishersCreated,
+		func() (uuid.UUID, error) {
+			publisher, err := r.publisherRepo.FindByName(ctx, name)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return publisher.ID, nil
+		},
+		func() (uuid.UUID, error) {
+			publisher := &domain.Publisher{Name: name}
+			if err := r.publisherRepo.Create(ctx, publisher); err != nil {
+				return uuid.Nil, fmt.Errorf("failed to create publisher %q: %w", name, err)
+			}
+			return publisher.ID, nil
+		})
+}
+
+func (r *importRun) resolveAuthor(ctx context.Context, name string) (uuid.UUID, error) {
+	if name == "" {
+		return uuid.Nil, fmt.Errorf("%w: author name is empty", ErrInvalidInput)
+	}
+	return r.resolve(r.authors, strings.ToLower(name), fmt.Sprintf("author %q", name), &r.report.AuthorsCreated,
+		func() (uuid.UUID, error) {
+			author, err := r.authorRepo.FindByName(ctx, name)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return author.ID, nil
+		},
+		func() (uuid.UUID, error) {
+			author := &domain.Author{Name: name}
+			if err := r.authorRepo.Create(ctx, author); err != nil {
+				return uuid.Nil, fmt.Errorf("failed to create author %q: %w", name, err)
+			}
+			return author.ID, nil
+		})
+}
+
+// resolveCategory matches a category by slug, so a category's name finds it
+// as long as its slug was generated from that name
+func (r *importRun) resolveCategory(ctx context.Context, name string) (uuid.UUID, error) {
+	slug := slugify(name)
+	if slug == "" {
+		return uuid.Nil, fmt.Errorf("%w: category %q has no usable slug", ErrInvalidInput, name)
+	}
+	return r.resolve(r.categories, slug, fmt.Sprintf("category %q", name), &r.report.CategoriesCreated,
+		func() (uuid.UUID, error) {
+			category, err := r.categoryRepo.FindBySlug(ctx, slug)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return category.ID, nil
+		},
+		func() (uuid.UUID, error) {
+			category := &domain.Category{Name: name, Slug: slug}
+			if err := r.categoryRepo.Create(ctx, category); err != nil {
+				return uuid.Nil, fmt.Errorf("failed to create category %q: %w", name, err)
+			}
+			return category.ID, nil
+		})
+}
+
+// resolve returns the ID cached under key, or looks it up with find and, when
+// there is none, creates it with create and counts it in created. label names
+// what was created in the row's error should the row fail.
+func (r *importRun) resolve(cache map[string]uuid.UUID, key, label string, created *int, find, create func() (uuid.UUID, error)) (uuid.UUID, error) {
+	if id, ok := cache[key]; ok {
+		return id, nil
+	}
+
+	id, err := find()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !r.dryRun {
+			if id, err = create(); err != nil {
+				return uuid.Nil, err
+			}
+			r.rowCreated = append(r.rowCreated, label)
+		}
+		*created++
+	} else if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to look up %q: %w", key, err)
+	}
+
+	cache[key] = id
+	return id, nil
+}
+
+// appendImportLink adds id unless the row already named it. Every name a dry
+// run would create is uuid.Nil, so those are all kept.
+func appendImportLink(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	for _, existing := range ids {
+		if existing == id && id != uuid.Nil {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// parseImportDate accepts YYYY-MM-DD or an RFC 3339 timestamp
+func parseImportDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, strings.TrimSpace(*value)); err == nil {
+			return &date, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: publication_date must be YYYY-MM-DD or RFC 3339", ErrInvalidInput)
+}
+
+func setImportString(field *string, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
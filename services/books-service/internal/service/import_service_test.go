@@ -0,0 +1,143 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+func TestSameImportedFields(t *testing.T) {
+	published := time.Date(2015, 10, 26, 0, 0, 0, 0, time.UTC)
+	threshold := 5
+
+	tests := []struct {
+		name   string
+		change func(book *domain.Book)
+		want   bool
+	}{
+		{name: "nothing changed", change: func(book *domain.Book) {}, want: true},
+		{
+			name: "fields an import doesn't set",
+			change: func(book *domain.Book) {
+				book.StockQuantity = 12
+				book.Version = 4
+				book.Metadata = `{"edition":2}`
+			},
+			want: true,
+		},
+		{
+			name: "same publication date in another zone",
+			change: func(book *domain.Book) {
+				date := published.In(time.FixedZone("EST", -5*3600))
+				book.PublicationDate = &date
+			},
+			want: true,
+		},
+		{name: "same reorder threshold", change: func(book *domain.Book) { book.ReorderThreshold = intPtr(threshold) }, want: true},
+		{name: "isbn", change: func(book *domain.Book) { book.ISBN = "9780306406157" }},
+		{name: "title", change: func(book *domain.Book) { book.Title = "Another Title" }},
+		{name: "description", change: func(book *domain.Book) { book.Description = "" }},
+		{name: "language", change: func(book *domain.Book) { book.Language = "es" }},
+		{name: "format", change: func(book *domain.Book) { book.Format = "hardcover" }},
+		{name: "cover image", change: func(book *domain.Book) { book.CoverImageURL = "https://example.com/cover.jpg" }},
+		{name: "pages", change: func(book *domain.Book) { book.Pages = 321 }},
+		{name: "price", change: func(book *domain.Book) { book.Price = 40 }},
+		{
+			name: "publication date",
+			change: func(book *domain.Book) {
+				date := published.AddDate(0, 0, 1)
+				book.PublicationDate = &date
+			},
+		},
+		{name: "publication date cleared", change: func(book *domain.Book) { book.PublicationDate = nil }},
+		{name: "reorder threshold", change: func(book *domain.Book) { book.ReorderThreshold = intPtr(threshold + 1) }},
+		{name: "reorder threshold cleared", change: func(book *domain.Book) { book.ReorderThreshold = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := testPatchBook(uuid.New(), published, threshold)
+			updated := *book
+			tt.change(&updated)
+
+			if got := sameImportedFields(book, &updated); got != tt.want {
+				t.Errorf("sameImportedFields() = %v, want %v", got, tt.want)
+			}
+			if got := sameImportedFields(&updated, book); got != tt.want {
+				t.Errorf("sameImportedFields() the other way round = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameImportLinks(t *testing.T) {
+	publisherID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	fiction, classics := uuid.New(), uuid.New()
+	other := uuid.New()
+
+	book := &domain.Book{
+		PublisherID: &publisherID,
+		Authors:     []domain.Author{{ID: first}, {ID: second}},
+		Categories:  []domain.Category{{ID: fiction}, {ID: classics}},
+	}
+
+	tests := []struct {
+		name  string
+		book  *domain.Book
+		links importLinks
+		want  bool
+	}{
+		{name: "row without links", book: book, want: true},
+		{name: "same publisher", book: book, links: importLinks{publisherID: &publisherID}, want: true},
+		{name: "other publisher", book: book, links: importLinks{publisherID: &other}},
+		{name: "publisher for a book without one", book: &domain.Book{}, links: importLinks{publisherID: &publisherID}},
+		{name: "publisher a dry run would create", book: book, links: importLinks{publisherID: &uuid.Nil}},
+		{name: "same authors", book: book, links: importLinks{authorIDs: []uuid.UUID{first, second}}, want: true},
+		{name: "authors reordered", book: book, links: importLinks{authorIDs: []uuid.UUID{second, first}}},
+		{name: "author dropped", book: book, links: importLinks{authorIDs: []uuid.UUID{first}}},
+		{name: "author added", book: book, links: importLinks{authorIDs: []uuid.UUID{first, second, other}}},
+		{name: "empty author list", book: book, links: importLinks{authorIDs: []uuid.UUID{}}},
+		{name: "empty author list for a book without authors", book: &domain.Book{}, links: importLinks{authorIDs: []uuid.UUID{}}, want: true},
+		{name: "same categories", book: book, links: importLinks{categoryIDs: []uuid.UUID{fiction, classics}}, want: true},
+		{name: "categories in another order", book: book, links: importLinks{categoryIDs: []uuid.UUID{classics, fiction}}, want: true},
+		{name: "category swapped", book: book, links: importLinks{categoryIDs: []uuid.UUID{fiction, other}}},
+		{name: "category dropped", book: book, links: importLinks{categoryIDs: []uuid.UUID{fiction}}},
+		{name: "category a dry run would create", book: book, links: importLinks{categoryIDs: []uuid.UUID{fiction, uuid.Nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameImportLinks(tt.book, &tt.links); got != tt.want {
+				t.Errorf("sameImportLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendImportLink(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+		id   uuid.UUID
+		want []uuid.UUID
+	}{
+		{name: "first link", ids: []uuid.UUID{}, id: first, want: []uuid.UUID{first}},
+		{name: "new link goes last", ids: []uuid.UUID{second}, id: first, want: []uuid.UUID{second, first}},
+		{name: "repeated link", ids: []uuid.UUID{first, second}, id: first, want: []uuid.UUID{first, second}},
+		{name: "names a dry run would create are all kept", ids: []uuid.UUID{uuid.Nil}, id: uuid.Nil, want: []uuid.UUID{uuid.Nil, uuid.Nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appendImportLink(tt.ids, tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appendImportLink(%v, %s) = %v, want %v", tt.ids, tt.id, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"github.com/youngermaster/bookstore/services/books-service/pkg/logclient"
+	"gorm.io/gorm"
+)
+
+// alertBatchSize caps how many pending alerts are delivered per check
+const alertBatchSize = 100
+
+// LogWriter delivers entries to the logging service
+type LogWriter interface {
+	Write(ctx context.Context, entry logclient.Entry) error
+}
+
+// InventoryService defines the interface for auditing stock against the ledger
+// and watching for low stock. Stock itself changes through
+// BookService.UpdateBookStock and reservations.
+type InventoryService interface {
+	ListMovements(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]domain.StockMovement, int64, error)
+	Reconcile(ctx context.Context, bookID uuid.UUID) (*domain.StockReconciliation, error)
+	ListDiscrepancies(ctx context.Context, limit int) ([]domain.StockReconciliation, error)
+	ListLowStock(ctx context.Context, limit, offset int) ([]domain.LowStockItem, int64, error)
+	CheckLowStock(ctx context.Context) (int64, error)
+}
+
+type inventoryService struct {
+	movementRepo repository.StockMovementRepository
+	bookRepo     repository.BookRepository
+	alertRepo    repository.StockAlertRepository
+	logWriter    LogWriter
+}
+
+// NewInventoryService creates a new instance of InventoryService. Low-stock
+// alerts are written to logWriter.
+func NewInventoryService(
+	movementRepo repository.StockMovementRepository,
+	bookRepo repository.BookRepository,
+	alertRepo repository.StockAlertRepository,
+	logWriter LogWriter,
+) InventoryService {
+	return &inventoryService{
+		movementRepo: movementRepo,
+		bookRepo:     bookRepo,
+		alertRepo:    alertRepo,
+		logWriter:    logWriter,
+	}
+}
+
+func (s *inventoryService) ListMovements(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]domain.StockMovement, int64, error) {
+	limit, offset = normalizePagination(limit, offset)
+
+	if _, err := s.bookRepo.FindByID(ctx, bookID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrBookNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to check book: %w", err)
+	}
+
+	movements, total, err := s.movementRepo.FindByBookID(ctx, bookID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+	return movements, total, nil
+}
+
+func (s *inventoryService) Reconcile(ctx context.Context, bookID uuid.UUID) (*domain.StockReconciliation, error) {
+	reconciliation, err := s.movementRepo.Reconcile(ctx, bookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, fmt.Errorf("failed to reconcile stock: %w", err)
+	}
+	return reconciliation, nil
+}
+
+func (s *inventoryService) ListDiscrepancies(ctx context.Context, limit int) ([]domain.StockReconciliation, error) {
+	limit, _ = normalizePagination(limit, 0)
+
+	discrepancies, err := s.movementRepo.FindDiscrepancies(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stock discrepancies: %w", err)
+	}
+	return discrepancies, nil
+}
+
+func (s *inventoryService) ListLowStock(ctx context.Context, limit, offset int) ([]domain.LowStockItem, int64, error) {
+	limit, offset = normalizePagination(limit, offset)
+
+	items, total, err := s.alertRepo.FindLowStock(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list low stock: %w", err)
+	}
+	return items, total, nil
+}
+
+// CheckLowStock raises an alert for every book that has fallen to its
+// threshold since the last check, closes the alerts of restocked books and
+// writes pending alerts to the logging service at WARN level. Alerts that
+// can't be delivered are retried on the next check. It returns how many
+// alerts were raised.
+func (s *inventoryService) CheckLowStock(ctx context.Context) (int64, error) {
+	if _, err := s.alertRepo.ResolveRecovered(ctx); err != nil {
+		return 0, fmt.Errorf("failed to resolve stock alerts: %w", err)
+	}
+
+	opened, err := s.alertRepo.Open(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open stock alerts: %w", err)
+	}
+
+	pending, err := s.alertRepo.FindUnnotified(ctx, alertBatchSize)
+	if err != nil {
+		return opened, fmt.Errorf("failed to find pending stock alerts: %w", err)
+	}
+	for i := range pending {
+		if err := s.logWriter.Write(ctx, lowStockEntry(&pending[i])); err != nil {
+			return opened, fmt.Errorf("failed to write stock alert: %w", err)
+		}
+		if err := s.alertRepo.MarkNotified(ctx, pending[i].ID); err != nil {
+			return opened, fmt.Errorf("failed to mark stock alert notified: %w", err)
+		}
+	}
+
+	return opened, nil
+}
+
+// lowStockEntry describes an alert as a logging service entry. The metadata
+// carries the "stock.low" event for consumers that filter on it.
+func lowStockEntry(alert *domain.StockAlert) logclient.Entry {
+	title, isbn := "", ""
+	if alert.Book != nil {
+		title, isbn = alert.Book.Title, alert.Book.ISBN
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"event":          "stock.low",
+		"alert_id":       alert.ID,
+		"book_id":        alert.BookID,
+		"isbn":           isbn,
+		"stock_quantity": alert.StockQuantity,
+		"threshold":      alert.Threshold,
+	})
+
+	return logclient.Entry{
+		Level:     logclient.LevelWarn,
+		Message:   fmt.Sprintf("Low stock: %q has %d left (reorder threshold %d)", title, alert.StockQuantity, alert.Threshold),
+		Metadata:  string(metadata),
+		Timestamp: alert.CreatedAt,
+	}
+}
@@ -0,0 +1,595 @@
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+)
+
+// onixLanguages maps the ISO 639-2/B codes ONIX uses to the two-letter codes
+// books are stored with. Other codes are kept as they are.
+var onixLanguages = map[string]string{
+	"ara": "ar", "chi": "zh", "cze": "cs", "dan": "da", "dut": "nl",
+	"eng": "en", "fin": "fi", "fre": "fr", "ger": "de", "gre": "el",
+	"heb": "he", "hin": "hi", "hun": "hu", "ita": "it", "jpn": "ja",
+	"kor": "ko", "nor": "no", "pol": "pl", "por": "pt", "rum": "ro",
+	"rus": "ru", "spa": "es", "swe": "sv", "tur": "tr", "ukr": "uk",
+}
+
+// onixFormats maps ONIX product forms (code list 150) to book formats
+var onixFormats = map[string]string{
+	"BB": "hardcover",
+	"BC": "paperback",
+	"EA": "ebook",
+	"EB": "ebook",
+	"ED": "ebook",
+}
+
+// onixSubjectSchemes names the subject schemes (code list 27) whose codes
+// become categories, such as "BISAC FIC000000"
+var onixSubjectSchemes = map[string]string{
+	"10": "BISAC",
+	"12": "BIC",
+	"93": "Thema",
+}
+
+type onixImportReader struct {
+	decoder  *xml.Decoder
+	currency string
+	// defaultCurrency applies to prices without a CurrencyCode
+	defaultCurrency string
+}
+
+// newONIXImportReader checks that r holds an ONIX 3.0 message with reference
+// tags. Short tags and earlier releases aren't supported. Only prices in the
+// given currency are read.
+func newONIXImportReader(r io.Reader, currency string) (*onixImportReader, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: ONIX message is empty", ErrInvalidInput)
+		}
+		if err != nil {
+			return nil, onixError(err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "ONIXMessage":
+		case "ONIXmessage":
+			return nil, fmt.Errorf("%w: short-tag ONIX isn't supported, send reference tags", ErrInvalidInput)
+		default:
+			return nil, fmt.Errorf("%w: expected an ONIXMessage, got <%s>", ErrInvalidInput, start.Name.Local)
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "release" && strings.HasPrefix(attr.Value, "3.") {
+				return &onixImportReader{decoder: decoder, currency: currency}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: only ONIX 3.0 messages are supported", ErrInvalidInput)
+	}
+}
+
+// Next decodes the next Product of the message, taking the default currency
+// from the Header on the way
+func (r *onixImportReader) Next() (*domain.BookImportRow, error) {
+	for {
+		token, err := r.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, onixError(err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "Header" {
+			var header onixHeader
+			if err := r.decoder.DecodeElement(&header, &start); err != nil {
+				return nil, onixError(err)
+			}
+			r.defaultCurrency = strings.ToUpper(strings.TrimSpace(header.DefaultCurrency))
+			continue
+		}
+		if start.Name.Local != "Product" {
+			continue
+		}
+
+		line, _ := r.decoder.InputPos()
+		var product onixProduct
+		if err := r.decoder.DecodeElement(&product, &start); err != nil {
+			return nil, onixError(err)
+		}
+		row, err := product.importRow(r.currency, r.defaultCurrency)
+		row.Line = line
+		return row, err
+	}
+}
+
+// onixError makes malformed XML invalid input and passes read errors through
+func onixError(err error) error {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: invalid ONIX: %v", ErrInvalidInput, err)
+	}
+	return err
+}
+
+// onixHeader holds the message defaults that products rely on
+type onixHeader struct {
+	DefaultCurrency string `xml:"DefaultCurrencyCode"`
+}
+
+// onixProduct holds the parts of an ONIX 3.0 Product that map onto a book
+type onixProduct struct {
+	RecordReference  string                  `xml:"RecordReference"`
+	NotificationType string                  `xml:"NotificationType"`
+	Identifiers      []onixProductIdentifier `xml:"ProductIdentifier"`
+	Descriptive      onixDescriptiveDetail   `xml:"DescriptiveDetail"`
+	Collateral       onixCollateralDetail    `xml:"CollateralDetail"`
+	Publishing       onixPublishingDetail    `xml:"PublishingDetail"`
+	Supply           []onixProductSupply     `xml:"ProductSupply"`
+}
+
+type onixProductIdentifier struct {
+	Type  string `xml:"ProductIDType"`
+	Value string `xml:"IDValue"`
+}
+
+type onixDescriptiveDetail struct {
+	ProductForm  string            `xml:"ProductForm"`
+	Titles       []onixTitleDetail `xml:"TitleDetail"`
+	Contributors []onixContributor `xml:"Contributor"`
+	Languages    []onixLanguage    `xml:"Language"`
+	Extents      []onixExtent      `xml:"Extent"`
+	Subjects     []onixSubject     `xml:"Subject"`
+}
+
+type onixTitleDetail struct {
+	Type     string             `xml:"TitleType"`
+	Elements []onixTitleElement `xml:"TitleElement"`
+}
+
+type onixTitleElement struct {
+	Level         string `xml:"TitleElementLevel"`
+	Text          string `xml:"TitleText"`
+	Prefix        string `xml:"TitlePrefix"`
+	WithoutPrefix string `xml:"TitleWithoutPrefix"`
+	Subtitle      string `xml:"Subtitle"`
+}
+
+type onixContributor struct {
+	SequenceNumber int      `xml:"SequenceNumber"`
+	Roles          []string `xml:"ContributorRole"`
+	PersonName     string   `xml:"PersonName"`
+	NamesBeforeKey string   `xml:"NamesBeforeKey"`
+	KeyNames       string   `xml:"KeyNames"`
+	CorporateName  string   `xml:"CorporateName"`
+}
+
+type onixLanguage struct {
+	Role string `xml:"LanguageRole"`
+	Code string `xml:"LanguageCode"`
+}
+
+type onixExtent struct {
+	Type  string `xml:"ExtentType"`
+	Value string `xml:"ExtentValue"`
+	Unit  string `xml:"ExtentUnit"`
+}
+
+type onixSubject struct {
+	Scheme string `xml:"SubjectSchemeIdentifier"`
+	Code   string `xml:"SubjectCode"`
+}
+
+type onixCollateralDetail struct {
+	Texts     []onixTextContent        `xml:"TextContent"`
+	Resources []onixSupportingResource `xml:"SupportingResource"`
+}
+
+type onixTextContent struct {
+	Type  string     `xml:"TextType"`
+	Texts []onixText `xml:"Text"`
+}
+
+// onixText keeps the raw markup of XHTML text and the plain text of the rest
+type onixText struct {
+	Format string `xml:"textformat,attr"`
+	Inner  string `xml:",innerxml"`
+	Plain  string `xml:",chardata"`
+}
+
+type onixSupportingResource struct {
+	ContentType string                `xml:"ResourceContentType"`
+	Mode        string                `xml:"ResourceMode"`
+	Versions    []onixResourceVersion `xml:"ResourceVersion"`
+}
+
+type onixResourceVersion struct {
+	Links []string `xml:"ResourceLink"`
+}
+
+type onixPublishingDetail struct {
+	Imprints   []onixImprint        `xml:"Imprint"`
+	Publishers []onixPublisher      `xml:"Publisher"`
+	Dates      []onixPublishingDate `xml:"PublishingDate"`
+}
+
+type onixImprint struct {
+	Name string `xml:"ImprintName"`
+}
+
+type onixPublisher struct {
+	Role string `xml:"PublishingRole"`
+	Name string `xml:"PublisherName"`
+}
+
+type onixPublishingDate struct {
+	Role string   `xml:"PublishingDateRole"`
+	Date onixDate `xml:"Date"`
+}
+
+type onixDate struct {
+	Format string `xml:"dateformat,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type onixProductSupply struct {
+	Details []onixSupplyDetail `xml:"SupplyDetail"`
+}
+
+type onixSupplyDetail struct {
+	Availability string      `xml:"ProductAvailability"`
+	Stock        []onixStock `xml:"Stock"`
+	Prices       []onixPrice `xml:"Price"`
+}
+
+type onixStock struct {
+	OnHand string `xml:"OnHand"`
+}
+
+type onixPrice struct {
+	Type     string `xml:"PriceType"`
+	Amount   string `xml:"PriceAmount"`
+	Currency string `xml:"CurrencyCode"`
+}
+
+// importRow maps the product onto an import row, with its price in the given
+// currency. Fields the product doesn't carry are left nil so an existing book
+// keeps them.
+func (p *onixProduct) importRow(currency, defaultCurrency string) (*domain.BookImportRow, error) {
+	row := &domain.BookImportRow{
+		RecordReference: strings.TrimSpace(p.RecordReference),
+		ISBN:            p.isbn(),
+	}
+	if p.NotificationType == "05" {
+		return row, fmt.Errorf("%w: delete notifications aren't supported, remove the book instead", ErrInvalidInput)
+	}
+
+	row.Title = p.title()
+	row.Description = p.description()
+	row.Publisher = p.publisher()
+	row.PublicationDate = p.publicationDate()
+	row.Language = p.language()
+	row.Pages = p.pages()
+	row.Format = p.format()
+	row.CoverImageURL = p.coverImageURL()
+	row.Authors = p.authors()
+	row.Categories = p.categories()
+
+	price, stock, err := p.supply(currency, defaultCurrency)
+	if err != nil {
+		return row, err
+	}
+	row.Price = price
+	row.StockQuantity = stock
+	return row, nil
+}
+
+// isbn prefers the ISBN-13, then a GTIN-13 that is a Bookland EAN, then the
+// ISBN-10
+func (p *onixProduct) isbn() string {
+	for _, idType := range []string{"15", "03", "02"} {
+		for _, identifier := range p.Identifiers {
+			value := strings.TrimSpace(identifier.Value)
+			if identifier.Type != idType {
+				continue
+			}
+			if idType == "03" && !strings.HasPrefix(value, "978") && !strings.HasPrefix(value, "979") {
+				continue
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// title is the distinctive title of the product, with its subtitle
+func (p *onixProduct) title() *string {
+	for _, detail := range p.Descriptive.Titles {
+		if detail.Type != "01" {
+			continue
+		}
+		for _, element := range detail.Elements {
+			if element.Level != "01" {
+				continue
+			}
+			title := strings.TrimSpace(element.Text)
+			if title == "" {
+				title = strings.TrimSpace(strings.TrimSpace(element.Prefix) + " " + strings.TrimSpace(element.WithoutPrefix))
+			}
+			if subtitle := strings.TrimSpace(element.Subtitle); subtitle != "" {
+				title += ": " + subtitle
+			}
+			if title != "" {
+				return &title
+			}
+		}
+	}
+	return nil
+}
+
+// description is the main description, or else the short one
+func (p *onixProduct) description() *string {
+	for _, textType := range []string{"03", "02"} {
+		for _, content := range p.Collateral.Texts {
+			if content.Type != textType || len(content.Texts) == 0 {
+				continue
+			}
+			text := content.Texts[0]
+			description := strings.TrimSpace(text.Plain)
+			if text.Format == "05" {
+				description = strings.TrimSpace(text.Inner)
+			}
+			if description != "" {
+				return &description
+			}
+		}
+	}
+	return nil
+}
+
+// publisher is the imprint the book is sold under, or else its publisher
+func (p *onixProduct) publisher() *string {
+	for _, imprint := range p.Publishing.Imprints {
+		if name := strings.TrimSpace(imprint.Name); name != "" {
+			return &name
+		}
+	}
+	for _, publisher := range p.Publishing.Publishers {
+		if name := strings.TrimSpace(publisher.Name); name != "" && publisher.Role == "01" {
+			return &name
+		}
+	}
+	return nil
+}
+
+// publicationDate is the publication date as YYYY-MM-DD. Dates given to the
+// month or year fall on the first day of it.
+func (p *onixProduct) publicationDate() *string {
+	for _, date := range p.Publishing.Dates {
+		if date.Role != "01" {
+			continue
+		}
+		value := strings.TrimSpace(date.Date.Value)
+		switch date.Date.Format {
+		case "", "00": // YYYYMMDD
+		case "01": // YYYYMM
+			value += "01"
+		case "05": // YYYY
+			value += "0101"
+		default:
+			continue
+		}
+		if len(value) != len("YYYYMMDD") {
+			// The import rejects it as it is
+			return &value
+		}
+		formatted := value[0:4] + "-" + value[4:6] + "-" + value[6:8]
+		return &formatted
+	}
+	return nil
+}
+
+// language is the language of the text
+func (p *onixProduct) language() *string {
+	for _, language := range p.Descriptive.Languages {
+		if language.Role != "01" {
+			continue
+		}
+		code := strings.ToLower(strings.TrimSpace(language.Code))
+		if short, ok := onixLanguages[code]; ok {
+			code = short
+		}
+		if code != "" {
+			return &code
+		}
+	}
+	return nil
+}
+
+// pages is the main content page count, or else the total numbered pages
+func (p *onixProduct) pages() *int {
+	for _, extentType := range []string{"00", "11", "07"} {
+		for _, extent := range p.Descriptive.Extents {
+			if extent.Type != extentType || extent.Unit != "03" {
+				continue
+			}
+			if pages, err := strconv.Atoi(strings.TrimSpace(extent.Value)); err == nil {
+				return &pages
+			}
+		}
+	}
+	return nil
+}
+
+func (p *onixProduct) format() *string {
+	if format, ok := onixFormats[strings.TrimSpace(p.Descriptive.ProductForm)]; ok {
+		return &format
+	}
+	return nil
+}
+
+// coverImageURL is the link to the front cover image
+func (p *onixProduct) coverImageURL() *string {
+	for _, resource := range p.Collateral.Resources {
+		if resource.ContentType != "01" || resource.Mode != "03" {
+			continue
+		}
+		for _, version := range resource.Versions {
+			for _, link := range version.Links {
+				if link = strings.TrimSpace(link); link != "" {
+					return &link
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// authors are the names of the authors and editors, in sequence order
+func (p *onixProduct) authors() []string {
+	contributors := make([]onixContributor, 0, len(p.Descriptive.Contributors))
+	for _, contributor := range p.Descriptive.Contributors {
+		for _, role := range contributor.Roles {
+			// A: creators such as authors, B: editors and adapters
+			if strings.HasPrefix(role, "A") || strings.HasPrefix(role, "B") {
+				contributors = append(contributors, contributor)
+				break
+			}
+		}
+	}
+	if len(contributors) == 0 {
+		return nil
+	}
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].SequenceNumber < contributors[j].SequenceNumber
+	})
+
+	var names []string
+	for _, contributor := range contributors {
+		name := strings.TrimSpace(contributor.PersonName)
+		if name == "" {
+			name = strings.TrimSpace(strings.TrimSpace(contributor.NamesBeforeKey) + " " + strings.TrimSpace(contributor.KeyNames))
+		}
+		if name == "" {
+			name = strings.TrimSpace(contributor.CorporateName)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// categories name the product's BISAC, BIC and Thema subject codes
+func (p *onixProduct) categories() []string {
+	var names []string
+	for _, subject := range p.Descriptive.Subjects {
+		scheme, ok := onixSubjectSchemes[subject.Scheme]
+		code := strings.TrimSpace(subject.Code)
+		if ok && code != "" {
+			names = append(names, scheme+" "+code)
+		}
+	}
+	return names
+}
+
+// supply takes the price in the given currency from the first supply detail
+// with an RRP, or else a fixed retail price, or else any price, and the stock
+// from that detail's on-hand figures. Without them, a product that isn't
+// available has no stock. Prices without a CurrencyCode are in defaultCurrency.
+// A product priced only in other currencies is rejected rather than imported
+// at a price the store would misread.
+func (p *onixProduct) supply(currency, defaultCurrency string) (*float64, *int, error) {
+	var details []onixSupplyDetail
+	priced := false
+	for _, supply := range p.Supply {
+		for _, detail := range supply.Details {
+			priced = priced || len(detail.Prices) > 0
+			detail.Prices = pricesIn(detail.Prices, currency, defaultCurrency)
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return nil, nil, nil
+	}
+
+	detail := details[0]
+	var amount *float64
+	for _, priceType := range []string{"02", "01", ""} {
+		found, ok := findONIXPrice(details, priceType)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(found.Prices[0].Amount), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: PriceAmount must be a number", ErrInvalidInput)
+		}
+		detail, amount = found, &value
+		break
+	}
+
+	if amount == nil && priced {
+		return nil, nil, fmt.Errorf("%w: product has no price in %s", ErrInvalidInput, currency)
+	}
+
+	var stock *int
+	for _, level := range detail.Stock {
+		onHand, err := strconv.Atoi(strings.TrimSpace(level.OnHand))
+		if err != nil {
+			continue
+		}
+		if stock == nil {
+			stock = new(int)
+		}
+		*stock += onHand
+	}
+	// Availability codes 20-29 are available; the rest aren't, or not yet
+	if stock == nil && detail.Availability != "" && !strings.HasPrefix(detail.Availability, "2") {
+		stock = new(int)
+	}
+	return amount, stock, nil
+}
+
+// findONIXPrice returns the first supply detail with a price of the given
+// type, or of any type for "", with only that price left in it
+func findONIXPrice(details []onixSupplyDetail, priceType string) (onixSupplyDetail, bool) {
+	for _, detail := range details {
+		for _, price := range detail.Prices {
+			if priceType == "" || price.Type == priceType {
+				detail.Prices = []onixPrice{price}
+				return detail, true
+			}
+		}
+	}
+	return onixSupplyDetail{}, false
+}
+
+// pricesIn keeps the prices in the given currency
+func pricesIn(prices []onixPrice, currency, defaultCurrency string) []onixPrice {
+	var kept []onixPrice
+	for _, price := range prices {
+		code := strings.ToUpper(strings.TrimSpace(price.Currency))
+		if code == "" {
+			code = defaultCurrency
+		}
+		if code == currency {
+			kept = append(kept, price)
+		}
+	}
+	return kept
+}
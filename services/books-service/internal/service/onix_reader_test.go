@@ -0,0 +1,282 @@
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestONIXPublicationDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		dates string
+		want  string
+	}{
+		{
+			name:  "YYYYMMDD without a format",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>20151026</Date></PublishingDate>`,
+			want:  "2015-10-26",
+		},
+		{
+			name:  "YYYYMMDD",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="00">20151026</Date></PublishingDate>`,
+			want:  "2015-10-26",
+		},
+		{
+			name:  "YYYYMM is the first of the month",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="01">201510</Date></PublishingDate>`,
+			want:  "2015-10-01",
+		},
+		{
+			name:  "YYYY is the first of the year",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="05">2015</Date></PublishingDate>`,
+			want:  "2015-01-01",
+		},
+		{
+			name:  "surrounding whitespace",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date> 20151026 </Date></PublishingDate>`,
+			want:  "2015-10-26",
+		},
+		{
+			name: "other roles are ignored",
+			dates: `<PublishingDate><PublishingDateRole>11</PublishingDateRole><Date>20150101</Date></PublishingDate>` +
+				`<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>20151026</Date></PublishingDate>`,
+			want: "2015-10-26",
+		},
+		{
+			name: "unsupported formats are skipped",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="13">20151026T1200</Date></PublishingDate>` +
+				`<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="05">2015</Date></PublishingDate>`,
+			want: "2015-01-01",
+		},
+		{
+			name:  "other lengths are passed through as they are",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>2015-10-26</Date></PublishingDate>`,
+			want:  "2015-10-26",
+		},
+		{
+			name:  "too short for its format, for the import to reject",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>201510</Date></PublishingDate>`,
+			want:  "201510",
+		},
+		{
+			name:  "only an unsupported format",
+			dates: `<PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="13">20151026T1200</Date></PublishingDate>`,
+		},
+		{
+			name:  "no publication date",
+			dates: `<PublishingDate><PublishingDateRole>11</PublishingDateRole><Date>20151026</Date></PublishingDate>`,
+		},
+		{name: "no dates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := decodeONIXProduct(t, `<PublishingDetail>`+tt.dates+`</PublishingDetail>`)
+			got := product.publicationDate()
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("publicationDate() = %q, want nil", *got)
+				}
+				return
+			}
+			if got == nil || *got != tt.want {
+				t.Errorf("publicationDate() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestONIXAuthors(t *testing.T) {
+	tests := []struct {
+		name         string
+		contributors string
+		want         []string
+	}{
+		{
+			name: "ordered by sequence number",
+			contributors: `<Contributor><SequenceNumber>3</SequenceNumber><ContributorRole>A01</ContributorRole><PersonName>Third</PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>1</SequenceNumber><ContributorRole>A01</ContributorRole><PersonName>First</PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>2</SequenceNumber><ContributorRole>B01</ContributorRole><PersonName>Second</PersonName></Contributor>`,
+			want: []string{"First", "Second", "Third"},
+		},
+		{
+			name: "document order without sequence numbers",
+			contributors: `<Contributor><ContributorRole>A01</ContributorRole><PersonName>Alan Donovan</PersonName></Contributor>` +
+				`<Contributor><ContributorRole>A01</ContributorRole><PersonName>Brian Kernighan</PersonName></Contributor>`,
+			want: []string{"Alan Donovan", "Brian Kernighan"},
+		},
+		{
+			name: "only authors and editors",
+			contributors: `<Contributor><SequenceNumber>1</SequenceNumber><ContributorRole>A01</ContributorRole><PersonName>Author</PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>2</SequenceNumber><ContributorRole>A12</ContributorRole><PersonName>Illustrator</PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>3</SequenceNumber><ContributorRole>E07</ContributorRole><PersonName>Narrator</PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>4</SequenceNumber><ContributorRole>B06</ContributorRole><PersonName>Translator</PersonName></Contributor>`,
+			want: []string{"Author", "Illustrator", "Translator"},
+		},
+		{
+			name:         "any of several roles",
+			contributors: `<Contributor><ContributorRole>E07</ContributorRole><ContributorRole>A01</ContributorRole><PersonName>Reads Own Book</PersonName></Contributor>`,
+			want:         []string{"Reads Own Book"},
+		},
+		{
+			name: "names from their parts",
+			contributors: `<Contributor><SequenceNumber>1</SequenceNumber><ContributorRole>A01</ContributorRole><NamesBeforeKey>Alan A. A.</NamesBeforeKey><KeyNames>Donovan</KeyNames></Contributor>` +
+				`<Contributor><SequenceNumber>2</SequenceNumber><ContributorRole>A01</ContributorRole><KeyNames>Plato</KeyNames></Contributor>` +
+				`<Contributor><SequenceNumber>3</SequenceNumber><ContributorRole>A01</ContributorRole><CorporateName>The Go Authors</CorporateName></Contributor>`,
+			want: []string{"Alan A. A. Donovan", "Plato", "The Go Authors"},
+		},
+		{
+			name: "nameless contributors are skipped",
+			contributors: `<Contributor><SequenceNumber>1</SequenceNumber><ContributorRole>A01</ContributorRole><PersonName> </PersonName></Contributor>` +
+				`<Contributor><SequenceNumber>2</SequenceNumber><ContributorRole>A01</ContributorRole><PersonName>Named</PersonName></Contributor>`,
+			want: []string{"Named"},
+		},
+		{
+			name:         "no authors",
+			contributors: `<Contributor><ContributorRole>E07</ContributorRole><PersonName>Narrator</PersonName></Contributor>`,
+		},
+		{name: "no contributors"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := decodeONIXProduct(t, `<DescriptiveDetail>`+tt.contributors+`</DescriptiveDetail>`)
+			if got := product.authors(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestONIXPrice(t *testing.T) {
+	usd := `<Price><PriceType>01</PriceType><PriceAmount>12.50</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>`
+	rrp := `<Price><PriceType>02</PriceType><PriceAmount>9.99</PriceAmount></Price>`
+
+	tests := []struct {
+		name            string
+		supply          string
+		defaultCurrency string
+		want            float64
+		wantNone        bool
+		wantErr         bool
+	}{
+		{name: "price in the store's currency", supply: usd, want: 12.50},
+		{name: "rrp before fixed price", supply: usd + `<Price><PriceType>02</PriceType><PriceAmount>14.00</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>`, want: 14.00},
+		{name: "other currencies are skipped", supply: rrp + usd, defaultCurrency: "GBP", want: 12.50},
+		{name: "default currency applies", supply: rrp, defaultCurrency: "USD", want: 9.99},
+		{name: "currency codes ignore case", supply: `<Price><PriceAmount>5</PriceAmount><CurrencyCode>usd</CurrencyCode></Price>`, want: 5},
+		{name: "only other currencies", supply: rrp, defaultCurrency: "GBP", wantErr: true},
+		{name: "no currency at all", supply: rrp, wantErr: true},
+		{name: "amount that isn't a number", supply: `<Price><PriceAmount>free</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>`, wantErr: true},
+		{name: "no prices", supply: `<ProductAvailability>21</ProductAvailability>`, wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := decodeONIXProduct(t, `<ProductSupply><SupplyDetail>`+tt.supply+`</SupplyDetail></ProductSupply>`)
+			price, _, err := product.supply("USD", tt.defaultCurrency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("supply() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("supply() error = %v", err)
+			}
+			if tt.wantNone {
+				if price != nil {
+					t.Errorf("supply() price = %v, want nil", *price)
+				}
+				return
+			}
+			if price == nil || *price != tt.want {
+				t.Errorf("supply() price = %v, want %v", price, tt.want)
+			}
+		})
+	}
+}
+
+func TestONIXImportReader(t *testing.T) {
+	product := `<Product><RecordReference>ref-1</RecordReference>` +
+		`<ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780134190440</IDValue></ProductIdentifier>` +
+		`<ProductSupply><SupplyDetail><Price><PriceAmount>39.99</PriceAmount></Price></SupplyDetail></ProductSupply></Product>`
+
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "header currency applies to every product",
+			message: `<ONIXMessage release="3.0"><Header><DefaultCurrencyCode>USD</DefaultCurrencyCode></Header>` + product + product + `</ONIXMessage>`,
+			want:    []string{"9780134190440", "9780134190440"},
+		},
+		{name: "no products", message: `<ONIXMessage release="3.0"><Header/></ONIXMessage>`},
+		{name: "release 3.1", message: `<?xml version="1.0"?><ONIXMessage release="3.1"></ONIXMessage>`},
+		{name: "empty", message: ``, wantErr: true},
+		{name: "short tags", message: `<ONIXmessage release="3.0"></ONIXmessage>`, wantErr: true},
+		{name: "release 2.1", message: `<ONIXMessage release="2.1"></ONIXMessage>`, wantErr: true},
+		{name: "no release", message: `<ONIXMessage></ONIXMessage>`, wantErr: true},
+		{name: "another document", message: `<rss version="2.0"></rss>`, wantErr: true},
+		{name: "not xml", message: `isbn,title`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := newONIXImportReader(strings.NewReader(tt.message), "USD")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("newONIXImportReader() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newONIXImportReader() error = %v", err)
+			}
+
+			var got []string
+			for {
+				row, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				if row.RecordReference != "ref-1" || row.Price == nil || *row.Price != 39.99 {
+					t.Errorf("Next() = %+v", row)
+				}
+				got = append(got, row.ISBN)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read ISBNs %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestONIXImportReaderMalformedProduct(t *testing.T) {
+	reader, err := newONIXImportReader(strings.NewReader(`<ONIXMessage release="3.0"><Product><RecordReference>ref-1</Product>`), "USD")
+	if err != nil {
+		t.Fatalf("newONIXImportReader() error = %v", err)
+	}
+	if _, err := reader.Next(); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Next() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// decodeONIXProduct decodes the inner XML of a Product
+func decodeONIXProduct(t *testing.T, inner string) *onixProduct {
+	t.Helper()
+	var product onixProduct
+	if err := xml.Unmarshal([]byte(`<Product>`+inner+`</Product>`), &product); err != nil {
+		t.Fatalf("failed to decode product: %v", err)
+	}
+	return &product
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPublisherNotFound = errors.New("publisher not found")
+	ErrPublisherHasBooks = errors.New("publisher has books assigned")
+)
+
+// PublisherService defines the interface for publisher business logic
+type PublisherService interface {
+	CreatePublisher(ctx context.Context, publisher *domain.Publisher) error
+	GetPublisher(ctx context.Context, id uuid.UUID) (*domain.Publisher, error)
+	ListPublishers(ctx context.Context, limit, offset int) ([]domain.Publisher, int64, error)
+	UpdatePublisher(ctx context.Context, publisher *domain.Publisher) error
+	DeletePublisher(ctx context.Context, id uuid.UUID) error
+	ListPublisherBooks(ctx context.Context, id uuid.UUID, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error)
+}
+
+type publisherService struct {
+	publisherRepo repository.PublisherRepository
+	bookService   BookService
+}
+
+// NewPublisherService creates a new instance of PublisherService
+func NewPublisherService(publisherRepo repository.PublisherRepository, bookService BookService) PublisherService {
+	return &publisherService{
+		publisherRepo: publisherRepo,
+		bookService:   bookService,
+	}
+}
+
+func (s *publisherService) CreatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	if publisher == nil {
+		return ErrInvalidInput
+	}
+
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	if publisher.Name == "" {
+		return ErrInvalidInput
+	}
+
+	if err := s.publisherRepo.Create(ctx, publisher); err != nil {
+		return fmt.Errorf("failed to create publisher: %w", err)
+	}
+
+	return nil
+}
+
+func (s *publisherService) GetPublisher(ctx context.Context, id uuid.UUID) (*domain.Publisher, error) {
+	publisher, err := s.publisherRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPublisherNotFound
+		}
+		return nil, fmt.Errorf("failed to get publisher: %w", err)
+	}
+	return publisher, nil
+}
+
+func (s *publisherService) ListPublishers(ctx context.Context, limit, offset int) ([]domain.Publisher, int64, error) {
+	limit, offset = normalizePagination(limit, offset)
+
+	publishers, total, err := s.publisherRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list publishers: %w", err)
+	}
+
+	return publishers, total, nil
+}
+
+func (s *publisherService) UpdatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	if publisher == nil || publisher.ID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	if publisher.Name == "" {
+		return ErrInvalidInput
+	}
+
+	existing, err := s.GetPublisher(ctx, publisher.ID)
+	if err != nil {
+		return err
+	}
+	publisher.CreatedAt = existing.CreatedAt
+
+	if err := s.publisherRepo.Update(ctx, publisher); err != nil {
+		return fmt.Errorf("failed to update publisher: %w", err)
+	}
+
+	return nil
+}
+
+func (s *publisherService) DeletePublisher(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetPublisher(ctx, id); err != nil {
+		return err
+	}
+
+	bookCount, err := s.publisherRepo.CountBooks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to count books: %w", err)
+	}
+	if bookCount > 0 {
+		return ErrPublisherHasBooks
+	}
+
+	if err := s.publisherRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete publisher: %w", err)
+	}
+
+	return nil
+}
+
+func (s *publisherService) ListPublisherBooks(ctx context.Context, id uuid.UUID, page domain.PageRequest, filters map[string]interface{}) (*domain.BookPage, error) {
+	if _, err := s.GetPublisher(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if filters == nil {
+		filters = make(map[string]interface{})
+	}
+	filters["publisher_id"] = id
+
+	return s.bookService.ListBooks(ctx, page, filters)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationNotHeld  = errors.New("reservation is no longer held")
+	ErrTooManyReservations = errors.New("too many active reservations")
+)
+
+// expiredBatchSize caps how many expired holds are released per statement
+const expiredBatchSize = 100
+
+// ReservationService defines the interface for stock reservations during checkout
+type ReservationService interface {
+	CreateReservation(ctx context.Context, userID, bookID, warehouseID uuid.UUID, quantity int, ttl time.Duration) (*domain.Reservation, error)
+	GetReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error)
+	ConfirmReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error)
+	ReleaseReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error)
+	ReleaseExpired(ctx context.Context) (int, error)
+}
+
+type reservationService struct {
+	reservationRepo repository.ReservationRepository
+	bookRepo        repository.BookRepository
+	warehouseRepo   repository.WarehouseRepository
+	defaultTTL      time.Duration
+	maxTTL          time.Duration
+	maxHeld         int
+	maxQuantity     int
+}
+
+// NewReservationService creates a new instance of ReservationService.
+// Holds last defaultTTL unless the caller asks for a TTL of up to maxTTL.
+// Each user may have up to maxHeld unexpired holds of up to maxQuantity
+// copies each.
+func NewReservationService(
+	reservationRepo repository.ReservationRepository,
+	bookRepo repository.BookRepository,
+	warehouseRepo repository.WarehouseRepository,
+	defaultTTL, maxTTL time.Duration,
+	maxHeld, maxQuantity int,
+) ReservationService {
+	return &reservationService{
+		reservationRepo: reservationRepo,
+		bookRepo:        bookRepo,
+		warehouseRepo:   warehouseRepo,
+		defaultTTL:      defaultTTL,
+		maxTTL:          maxTTL,
+		maxHeld:         maxHeld,
+		maxQuantity:     maxQuantity,
+	}
+}
+
+// CreateReservation holds stock at the given warehouse, or at whichever
+// warehouse can cover the whole quantity when warehouseID is uuid.Nil
+func (s *reservationService) CreateReservation(ctx context.Context, userID, bookID, warehouseID uuid.UUID, quantity int, ttl time.Duration) (*domain.Reservation, error) {
+	if quantity <= 0 || ttl < 0 || ttl > s.maxTTL {
+		return nil, ErrInvalidInput
+	}
+	if quantity > s.maxQuantity {
+		return nil, fmt.Errorf("%w: quantity can't be more than %d", ErrInvalidInput, s.maxQuantity)
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+
+	if _, err := s.bookRepo.FindByID(ctx, bookID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, fmt.Errorf("failed to check book: %w", err)
+	}
+	if warehouseID != uuid.Nil {
+		if _, err := s.warehouseRepo.FindByID(ctx, warehouseID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrWarehouseNotFound
+			}
+			return nil, fmt.Errorf("failed to check warehouse: %w", err)
+		}
+	}
+
+	reservation := &domain.Reservation{
+		BookID:      bookID,
+		UserID:      userID,
+		WarehouseID: warehouseID,
+		Quantity:    quantity,
+		ExpiresAt:   time.Now().UTC().Add(ttl),
+	}
+	held, err := s.reservationRepo.Hold(ctx, reservation, s.maxHeld)
+	if errors.Is(err, repository.ErrTooManyHolds) {
+		return nil, fmt.Errorf("%w: release or confirm one of your %d held reservations first", ErrTooManyReservations, s.maxHeld)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to hold stock: %w", err)
+	}
+	if !held {
+		return nil, ErrInsufficientStock
+	}
+
+	return reservation, nil
+}
+
+func (s *reservationService) GetReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error) {
+	reservation, err := s.reservationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	// Other users' reservations are reported as missing
+	if reservation.UserID != userID {
+		return nil, ErrReservationNotFound
+	}
+
+	return reservation, nil
+}
+
+// ConfirmReservation turns a hold into a sale. Confirming twice is a no-op so
+// checkout retries are safe.
+func (s *reservationService) ConfirmReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error) {
+	reservation, err := s.GetReservation(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == domain.ReservationConfirmed {
+		return reservation, nil
+	}
+
+	confirmed, err := s.reservationRepo.Confirm(ctx, reservation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm reservation: %w", err)
+	}
+	if !confirmed {
+		return nil, ErrReservationNotHeld
+	}
+
+	return reservation, nil
+}
+
+// ReleaseReservation gives held stock back. Releasing a reservation that was
+// already released or has expired is a no-op.
+func (s *reservationService) ReleaseReservation(ctx context.Context, userID, id uuid.UUID) (*domain.Reservation, error) {
+	reservation, err := s.GetReservation(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	switch reservation.Status {
+	case domain.ReservationReleased, domain.ReservationExpired:
+		return reservation, nil
+	case domain.ReservationConfirmed:
+		return nil, ErrReservationNotHeld
+	}
+
+	released, err := s.reservationRepo.Release(ctx, reservation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release reservation: %w", err)
+	}
+	if !released {
+		// Confirmed or expired by a concurrent request
+		return nil, ErrReservationNotHeld
+	}
+
+	return reservation, nil
+}
+
+// ReleaseExpired returns the stock of every expired hold and reports how many
+// books were restocked
+func (s *reservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	restocked := 0
+	for {
+		bookIDs, err := s.reservationRepo.ReleaseExpired(ctx, time.Now().UTC(), expiredBatchSize)
+		if err != nil {
+			return restocked, fmt.Errorf("failed to release expired reservations: %w", err)
+		}
+		restocked += len(bookIDs)
+		if len(bookIDs) == 0 {
+			return restocked, nil
+		}
+	}
+}
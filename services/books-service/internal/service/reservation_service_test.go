@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	testMaxHeld     = 3
+	testMaxQuantity = 5
+	testDefaultTTL  = 15 * time.Minute
+	testMaxTTL      = time.Hour
+)
+
+func TestCreateReservation(t *testing.T) {
+	book, missing := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		bookID      uuid.UUID
+		warehouseID uuid.UUID
+		quantity    int
+		ttl         time.Duration
+		// stock is the book's stock, testMaxQuantity when zero
+		stock int
+		// wantErr is nil when the hold must succeed
+		wantErr error
+	}{
+		{name: "default ttl", bookID: book, quantity: 1},
+		{name: "longest ttl", bookID: book, quantity: 1, ttl: testMaxTTL},
+		{name: "largest quantity", bookID: book, quantity: testMaxQuantity},
+		{name: "at the default warehouse", bookID: book, warehouseID: testWarehouseID, quantity: 2},
+		{name: "no quantity", bookID: book, quantity: 0, wantErr: ErrInvalidInput},
+		{name: "negative quantity", bookID: book, quantity: -1, wantErr: ErrInvalidInput},
+		{name: "quantity over the cap", bookID: book, quantity: testMaxQuantity + 1, wantErr: ErrInvalidInput},
+		{name: "negative ttl", bookID: book, quantity: 1, ttl: -time.Minute, wantErr: ErrInvalidInput},
+		{name: "ttl over the cap", bookID: book, quantity: 1, ttl: testMaxTTL + time.Second, wantErr: ErrInvalidInput},
+		{name: "unknown book", bookID: missing, quantity: 1, wantErr: ErrBookNotFound},
+		{name: "unknown warehouse", bookID: book, warehouseID: uuid.New(), quantity: 1, wantErr: ErrWarehouseNotFound},
+		{name: "more than the stock", bookID: book, quantity: 2, stock: 1, wantErr: ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock := tt.stock
+			if stock == 0 {
+				stock = testMaxQuantity
+			}
+			service, repo := newTestReservationService(map[uuid.UUID]int{book: stock})
+
+			before := time.Now().UTC()
+			reservation, err := service.CreateReservation(context.Background(), uuid.New(), tt.bookID, tt.warehouseID, tt.quantity, tt.ttl)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateReservation() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := repo.stock[book]; got != stock {
+					t.Errorf("stock = %d after a failed hold, want %d", got, stock)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateReservation() error = %v", err)
+			}
+
+			ttl := tt.ttl
+			if ttl == 0 {
+				ttl = testDefaultTTL
+			}
+			if reservation.Status != domain.ReservationHeld || reservation.WarehouseID != testWarehouseID {
+				t.Errorf("reservation = %+v, want held at the default warehouse", reservation)
+			}
+			if reservation.ExpiresAt.Before(before.Add(ttl)) || reservation.ExpiresAt.After(time.Now().UTC().Add(ttl)) {
+				t.Errorf("expires at %v, want %v from now", reservation.ExpiresAt, ttl)
+			}
+			if got := repo.stock[book]; got != stock-tt.quantity {
+				t.Errorf("stock = %d, want %d", got, stock-tt.quantity)
+			}
+		})
+	}
+}
+
+func TestCreateReservationMaxHeld(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup holds copies of book for user before the hold under test
+		setup   func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID)
+		wantErr error
+	}{
+		{
+			name: "under the cap",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				holdTestReservations(t, s, user, book, testMaxHeld-1)
+			},
+		},
+		{
+			name: "at the cap",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				holdTestReservations(t, s, user, book, testMaxHeld)
+			},
+			wantErr: ErrTooManyReservations,
+		},
+		{
+			name: "expired holds don't count before the sweep",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				holdTestReservations(t, s, user, book, testMaxHeld)
+				repo.elapsed = testMaxTTL
+			},
+		},
+		{
+			name: "released holds don't count",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				held := holdTestReservations(t, s, user, book, testMaxHeld)
+				if _, err := s.ReleaseReservation(context.Background(), user, held[0].ID); err != nil {
+					t.Fatalf("ReleaseReservation() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "confirmed holds don't count",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				held := holdTestReservations(t, s, user, book, testMaxHeld)
+				if _, err := s.ConfirmReservation(context.Background(), user, held[0].ID); err != nil {
+					t.Fatalf("ConfirmReservation() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "other users' holds don't count",
+			setup: func(t *testing.T, s ReservationService, repo *fakeReservationRepository, user, book uuid.UUID) {
+				holdTestReservations(t, s, uuid.New(), book, testMaxHeld)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := uuid.New()
+			service, repo := newTestReservationService(map[uuid.UUID]int{book: 100})
+			user := uuid.New()
+			tt.setup(t, service, repo, user, book)
+
+			_, err := service.CreateReservation(context.Background(), user, book, uuid.Nil, 1, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateReservation() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestReservationTransitions follows a hold through confirms, releases, its
+// expiry and the expiry sweep, in every order a checkout can hit them
+func TestReservationTransitions(t *testing.T) {
+	type step struct {
+		// action is confirm, release, expire (the hold's time runs out) or
+		// sweep (ReleaseExpired runs)
+		action  string
+		wantErr error
+	}
+
+	tests := []struct {
+		name       string
+		steps      []step
+		wantStatus string
+		// wantReturned is true when the held copy must be back in stock
+		wantReturned bool
+	}{
+		{name: "held", wantStatus: domain.ReservationHeld},
+		{name: "confirm", steps: []step{{action: "confirm"}}, wantStatus: domain.ReservationConfirmed},
+		{name: "confirm twice", steps: []step{{action: "confirm"}, {action: "confirm"}}, wantStatus: domain.ReservationConfirmed},
+		{
+			name:       "confirm after expiry before the sweep",
+			steps:      []step{{action: "expire"}, {action: "confirm", wantErr: ErrReservationNotHeld}},
+			wantStatus: domain.ReservationHeld,
+		},
+		{
+			name:         "confirm after the sweep",
+			steps:        []step{{action: "expire"}, {action: "sweep"}, {action: "confirm", wantErr: ErrReservationNotHeld}},
+			wantStatus:   domain.ReservationExpired,
+			wantReturned: true,
+		},
+		{
+			name:         "confirm after release",
+			steps:        []step{{action: "release"}, {action: "confirm", wantErr: ErrReservationNotHeld}},
+			wantStatus:   domain.ReservationReleased,
+			wantReturned: true,
+		},
+		{name: "release", steps: []step{{action: "release"}}, wantStatus: domain.ReservationReleased, wantReturned: true},
+		{
+			name:         "release twice",
+			steps:        []step{{action: "release"}, {action: "release"}},
+			wantStatus:   domain.ReservationReleased,
+			wantReturned: true,
+		},
+		{
+			name:         "release after expiry before the sweep",
+			steps:        []step{{action: "expire"}, {action: "release"}, {action: "sweep"}},
+			wantStatus:   domain.ReservationReleased,
+			wantReturned: true,
+		},
+		{
+			name:         "release after the sweep",
+			steps:        []step{{action: "expire"}, {action: "sweep"}, {action: "release"}},
+			wantStatus:   domain.ReservationExpired,
+			wantReturned: true,
+		},
+		{
+			name:       "release after confirm",
+			steps:      []step{{action: "confirm"}, {action: "release", wantErr: ErrReservationNotHeld}},
+			wantStatus: domain.ReservationConfirmed,
+		},
+		{
+			name:       "sweep before expiry",
+			steps:      []step{{action: "sweep"}},
+			wantStatus: domain.ReservationHeld,
+		},
+		{
+			name:       "sweep after confirm",
+			steps:      []step{{action: "confirm"}, {action: "expire"}, {action: "sweep"}},
+			wantStatus: domain.ReservationConfirmed,
+		},
+		{
+			name:         "sweep twice",
+			steps:        []step{{action: "expire"}, {action: "sweep"}, {action: "sweep"}},
+			wantStatus:   domain.ReservationExpired,
+			wantReturned: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			book, user := uuid.New(), uuid.New()
+			const stock = 10
+			service, repo := newTestReservationService(map[uuid.UUID]int{book: stock})
+
+			reservation, err := service.CreateReservation(ctx, user, book, uuid.Nil, 2, 0)
+			if err != nil {
+				t.Fatalf("CreateReservation() error = %v", err)
+			}
+
+			for i, step := range tt.steps {
+				var err error
+				switch step.action {
+				case "confirm":
+					_, err = service.ConfirmReservation(ctx, user, reservation.ID)
+				case "release":
+					_, err = service.ReleaseReservation(ctx, user, reservation.ID)
+				case "expire":
+					repo.elapsed = testDefaultTTL + time.Minute
+				case "sweep":
+					_, err = service.ReleaseExpired(ctx)
+				}
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("step %d, %s: error = %v, want %v", i+1, step.action, err, step.wantErr)
+				}
+			}
+
+			got, err := service.GetReservation(ctx, user, reservation.ID)
+			if err != nil {
+				t.Fatalf("GetReservation() error = %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			wantStock := stock - 2
+			if tt.wantReturned {
+				wantStock = stock
+			}
+			if repo.stock[book] != wantStock {
+				t.Errorf("stock = %d, want %d", repo.stock[book], wantStock)
+			}
+		})
+	}
+}
+
+func TestReservationOfAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	book, user, other := uuid.New(), uuid.New(), uuid.New()
+	service, _ := newTestReservationService(map[uuid.UUID]int{book: 1})
+
+	reservation, err := service.CreateReservation(ctx, user, book, uuid.Nil, 1, 0)
+	if err != nil {
+		t.Fatalf("CreateReservation() error = %v", err)
+	}
+
+	if _, err := service.GetReservation(ctx, other, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("GetReservation() error = %v, want ErrReservationNotFound", err)
+	}
+	if _, err := service.ConfirmReservation(ctx, other, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("ConfirmReservation() error = %v, want ErrReservationNotFound", err)
+	}
+	if _, err := service.ReleaseReservation(ctx, other, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("ReleaseReservation() error = %v, want ErrReservationNotFound", err)
+	}
+}
+
+func TestReleaseExpired(t *testing.T) {
+	ctx := context.Background()
+	first, second, unexpired := uuid.New(), uuid.New(), uuid.New()
+	service, repo := newTestReservationService(map[uuid.UUID]int{first: 5, second: 5, unexpired: 5})
+
+	for _, hold := range []struct {
+		book uuid.UUID
+		ttl  time.Duration
+	}{
+		{book: first, ttl: time.Minute},
+		{book: first, ttl: time.Minute},
+		{book: second, ttl: time.Minute},
+		{book: unexpired, ttl: testMaxTTL},
+	} {
+		if _, err := service.CreateReservation(ctx, uuid.New(), hold.book, uuid.Nil, 1, hold.ttl); err != nil {
+			t.Fatalf("CreateReservation() error = %v", err)
+		}
+	}
+	repo.elapsed = 2 * time.Minute
+
+	restocked, err := service.ReleaseExpired(ctx)
+	if err != nil {
+		t.Fatalf("ReleaseExpired() error = %v", err)
+	}
+	if restocked != 2 {
+		t.Errorf("ReleaseExpired() = %d books, want 2", restocked)
+	}
+	for book, want := range map[uuid.UUID]int{first: 5, second: 5, unexpired: 4} {
+		if repo.stock[book] != want {
+			t.Errorf("stock of %s = %d, want %d", book, repo.stock[book], want)
+		}
+	}
+}
+
+// testWarehouseID is the only warehouse the fakes know
+var testWarehouseID = uuid.New()
+
+func newTestReservationService(stock map[uuid.UUID]int) (ReservationService, *fakeReservationRepository) {
+	repo := &fakeReservationRepository{
+		reservations: map[uuid.UUID]domain.Reservation{},
+		stock:        stock,
+	}
+	books := &fakeBookRepository{stock: stock}
+	warehouses := &fakeWarehouseRepository{}
+	return NewReservationService(repo, books, warehouses, testDefaultTTL, testMaxTTL, testMaxHeld, testMaxQuantity), repo
+}
+
+// holdTestReservations holds one copy of book n times for user
+func holdTestReservations(t *testing.T, s ReservationService, user, book uuid.UUID, n int) []*domain.Reservation {
+	t.Helper()
+	var held []*domain.Reservation
+	for i := 0; i < n; i++ {
+		reservation, err := s.CreateReservation(context.Background(), user, book, uuid.Nil, 1, 0)
+		if err != nil {
+			t.Fatalf("CreateReservation() error = %v", err)
+		}
+		held = append(held, reservation)
+	}
+	return held
+}
+
+// fakeReservationRepository keeps reservations and each book's stock at the
+// one test warehouse in memory, and follows the same rules as the Postgres
+// repository: confirming needs an unexpired hold, releasing only a held one,
+// and the sweep expires holds whose time ran out.
+type fakeReservationRepository struct {
+	reservations map[uuid.UUID]domain.Reservation
+	stock        map[uuid.UUID]int
+	// elapsed runs the repository's clock ahead of the real one, so holds
+	// expire without waiting
+	elapsed time.Duration
+}
+
+func (r *fakeReservationRepository) now() time.Time {
+	return time.Now().UTC().Add(r.elapsed)
+}
+
+func (r *fakeReservationRepository) Hold(ctx context.Context, reservation *domain.Reservation, maxHeld int) (bool, error) {
+	active := 0
+	for _, held := range r.reservations {
+		if held.UserID == reservation.UserID && held.Status == domain.ReservationHeld && held.ExpiresAt.After(r.now()) {
+			active++
+		}
+	}
+	if active >= maxHeld {
+		return false, repository.ErrTooManyHolds
+	}
+	if r.stock[reservation.BookID] < reservation.Quantity {
+		return false, nil
+	}
+
+	r.stock[reservation.BookID] -= reservation.Quantity
+	reservation.ID = uuid.New()
+	reservation.WarehouseID = testWarehouseID
+	reservation.Status = domain.ReservationHeld
+	r.reservations[reservation.ID] = *reservation
+	return true, nil
+}
+
+func (r *fakeReservationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &reservation, nil
+}
+
+func (r *fakeReservationRepository) Confirm(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	stored := r.reservations[reservation.ID]
+	if stored.Status != domain.ReservationHeld || !stored.ExpiresAt.After(r.now()) {
+		return false, nil
+	}
+	stored.Status = domain.ReservationConfirmed
+	r.reservations[stored.ID] = stored
+	reservation.Status = stored.Status
+	return true, nil
+}
+
+func (r *fakeReservationRepository) Release(ctx context.Context, reservation *domain.Reservation) (bool, error) {
+	stored := r.reservations[reservation.ID]
+	if stored.Status != domain.ReservationHeld {
+		return false, nil
+	}
+	stored.Status = domain.ReservationReleased
+	r.reservations[stored.ID] = stored
+	r.stock[stored.BookID] += stored.Quantity
+	reservation.Status = stored.Status
+	return true, nil
+}
+
+func (r *fakeReservationRepository) ReleaseExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	now = now.Add(r.elapsed)
+	restocked := map[uuid.UUID]bool{}
+	var bookIDs []uuid.UUID
+	for id, reservation := range r.reservations {
+		if limit == 0 {
+			break
+		}
+		if reservation.Status != domain.ReservationHeld || reservation.ExpiresAt.After(now) {
+			continue
+		}
+		reservation.Status = domain.ReservationExpired
+		r.reservations[id] = reservation
+		r.stock[reservation.BookID] += reservation.Quantity
+		if !restocked[reservation.BookID] {
+			restocked[reservation.BookID] = true
+			bookIDs = append(bookIDs, reservation.BookID)
+		}
+		limit--
+	}
+	return bookIDs, nil
+}
+
+// fakeBookRepository knows the books it has stock for. Only FindByID is
+// implemented.
+type fakeBookRepository struct {
+	repository.BookRepository
+	stock map[uuid.UUID]int
+}
+
+func (r *fakeBookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Book, error) {
+	stock, ok := r.stock[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &domain.Book{ID: id, StockQuantity: stock}, nil
+}
+
+// fakeWarehouseRepository knows only testWarehouseID. Only FindByID is
+// implemented.
+type fakeWarehouseRepository struct {
+	repository.WarehouseRepository
+}
+
+func (r *fakeWarehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	if id != testWarehouseID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &domain.Warehouse{ID: id, Code: domain.DefaultWarehouseCode}, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+)
+
+const (
+	minSuggestQueryLength = 2
+	maxSuggestQueryLength = 100
+	defaultSuggestLimit   = 5
+	maxSuggestLimit       = 10
+)
+
+// SuggestionService defines the interface for search box autocomplete
+type SuggestionService interface {
+	Suggest(ctx context.Context, query string, limit int) (*domain.Suggestions, error)
+}
+
+type suggestionService struct {
+	suggestionRepo repository.SuggestionRepository
+}
+
+// NewSuggestionService creates a new instance of SuggestionService
+func NewSuggestionService(suggestionRepo repository.SuggestionRepository) SuggestionService {
+	return &suggestionService{
+		suggestionRepo: suggestionRepo,
+	}
+}
+
+// Suggest returns title, author and category suggestions for a partial,
+// possibly misspelled query. Queries too short to match return empty groups.
+func (s *suggestionService) Suggest(ctx context.Context, query string, limit int) (*domain.Suggestions, error) {
+	if limit <= 0 || limit > maxSuggestLimit {
+		limit = defaultSuggestLimit
+	}
+
+	result := &domain.Suggestions{
+		Titles:     []domain.Suggestion{},
+		Authors:    []domain.Suggestion{},
+		Categories: []domain.Suggestion{},
+	}
+
+	query = strings.Join(strings.Fields(query), " ")
+	if utf8.RuneCountInString(query) < minSuggestQueryLength {
+		return result, nil
+	}
+	if utf8.RuneCountInString(query) > maxSuggestQueryLength {
+		return nil, ErrInvalidInput
+	}
+
+	// Fetch extra rows so duplicates (e.g. several editions of one title)
+	// can be dropped without coming up short
+	matches, err := s.suggestionRepo.Suggest(ctx, query, limit*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		var group *[]domain.Suggestion
+		switch match.Type {
+		case domain.SuggestionTypeTitle:
+			group = &result.Titles
+		case domain.SuggestionTypeAuthor:
+			group = &result.Authors
+		case domain.SuggestionTypeCategory:
+			group = &result.Categories
+		default:
+			continue
+		}
+
+		key := match.Type + ":" + strings.ToLower(match.Text)
+		if seen[key] || len(*group) >= limit {
+			continue
+		}
+		seen[key] = true
+		*group = append(*group, match)
+	}
+
+	return result, nil
+}
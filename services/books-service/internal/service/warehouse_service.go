@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/books-service/internal/domain"
+	"github.com/youngermaster/bookstore/services/books-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWarehouseNotFound      = errors.New("warehouse not found")
+	ErrWarehouseAlreadyExists = errors.New("warehouse with this code already exists")
+)
+
+// WarehouseService defines the interface for warehouse business logic
+type WarehouseService interface {
+	CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	ListWarehouses(ctx context.Context) ([]domain.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error
+}
+
+type warehouseService struct {
+	warehouseRepo repository.WarehouseRepository
+}
+
+// NewWarehouseService creates a new instance of WarehouseService
+func NewWarehouseService(warehouseRepo repository.WarehouseRepository) WarehouseService {
+	return &warehouseService{
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+func (s *warehouseService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	if warehouse == nil {
+		return ErrInvalidInput
+	}
+
+	warehouse.Code = strings.ToLower(strings.TrimSpace(warehouse.Code))
+	warehouse.Name = strings.TrimSpace(warehouse.Name)
+	if warehouse.Code == "" || warehouse.Name == "" {
+		return ErrInvalidInput
+	}
+
+	if err := s.checkCodeAvailable(ctx, warehouse.Code); err != nil {
+		return err
+	}
+
+	if err := s.warehouseRepo.Create(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to create warehouse: %w", err)
+	}
+
+	return nil
+}
+
+func (s *warehouseService) GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	return warehouse, nil
+}
+
+func (s *warehouseService) ListWarehouses(ctx context.Context) ([]domain.Warehouse, error) {
+	warehouses, err := s.warehouseRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouses: %w", err)
+	}
+	return warehouses, nil
+}
+
+func (s *warehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	if warehouse == nil || warehouse.ID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	warehouse.Code = strings.ToLower(strings.TrimSpace(warehouse.Code))
+	warehouse.Name = strings.TrimSpace(warehouse.Name)
+	if warehouse.Code == "" || warehouse.Name == "" {
+		return ErrInvalidInput
+	}
+
+	existing, err := s.GetWarehouse(ctx, warehouse.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Code != warehouse.Code {
+		// The default warehouse is looked up by its code
+		if existing.Code == domain.DefaultWarehouseCode {
+			return fmt.Errorf("%w: the default warehouse code can't change", ErrInvalidInput)
+		}
+		if err := s.checkCodeAvailable(ctx, warehouse.Code); err != nil {
+			return err
+		}
+	}
+	warehouse.CreatedAt = existing.CreatedAt
+
+	if err := s.warehouseRepo.Update(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to update warehouse: %w", err)
+	}
+
+	return nil
+}
+
+func (s *warehouseService) checkCodeAvailable(ctx context.Context, code string) error {
+	_, err := s.warehouseRepo.FindByCode(ctx, code)
+	if err == nil {
+		return ErrWarehouseAlreadyExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check warehouse code: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Package isbn validates ISBN-10 and ISBN-13 numbers and converts between them
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid ISBN")
+
+// Normalize validates an ISBN-10 or ISBN-13, ignoring hyphens and spaces, and
+// returns it as a bare ISBN-13
+func Normalize(value string) (string, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalid
+		}
+		body := "978" + digits[:9]
+		return body + string(isbn13CheckDigit(body)), nil
+	case 13:
+		if !validISBN13(digits) {
+			return "", ErrInvalid
+		}
+		return digits, nil
+	}
+	return "", ErrInvalid
+}
+
+// ToISBN10 returns the ISBN-10 of a normalized ISBN-13. Only the 978 prefix
+// has ISBN-10 equivalents.
+func ToISBN10(isbn13 string) (string, bool) {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return "", false
+	}
+	body := isbn13[3:12]
+	return body + string(isbn10CheckDigit(body)), true
+}
+
+func validISBN10(digits string) bool {
+	for i := 0; i < 9; i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return isbn10CheckDigit(digits[:9]) == digits[9]
+}
+
+func validISBN13(digits string) bool {
+	if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+		return false
+	}
+	for i := 0; i < 13; i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return isbn13CheckDigit(digits[:12]) == digits[12]
+}
+
+// isbn10CheckDigit computes the check digit for the first nine digits of an
+// ISBN-10, where X stands for 10
+func isbn10CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// isbn13CheckDigit computes the check digit for the first twelve digits of an
+// ISBN-13, weighting digits alternately by 1 and 3
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(body[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
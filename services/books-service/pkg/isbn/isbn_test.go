@@ -0,0 +1,90 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "isbn-13", value: "9780306406157", want: "9780306406157"},
+		{name: "isbn-13 with hyphens", value: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "isbn-13 with spaces", value: "978 0 306 40615 7", want: "9780306406157"},
+		{name: "isbn-13 with 979 prefix", value: "979-10-323-0569-0", want: "9791032305690"},
+		{name: "isbn-10", value: "0306406152", want: "9780306406157"},
+		{name: "isbn-10 with hyphens", value: "0-306-40615-2", want: "9780306406157"},
+		{name: "isbn-10 with check digit X", value: "0-8044-2957-X", want: "9780804429573"},
+		{name: "isbn-10 with lowercase x", value: "080442957x", want: "9780804429573"},
+		{name: "isbn-13 with wrong check digit", value: "9780306406158", wantErr: true},
+		{name: "isbn-10 with wrong check digit", value: "0306406153", wantErr: true},
+		{name: "isbn-10 with X where the check digit is 2", value: "030640615X", wantErr: true},
+		{name: "X before the check digit", value: "03064061X2", wantErr: true},
+		{name: "isbn-13 with X", value: "978030640615X", wantErr: true},
+		{name: "ean that isn't bookland", value: "4006381333931", wantErr: true},
+		{name: "letters", value: "97803064061AB", wantErr: true},
+		{name: "too short", value: "978030640615", wantErr: true},
+		{name: "too long", value: "97803064061570", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Normalize(%q) error = %v, want ErrInvalid", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToISBN10(t *testing.T) {
+	tests := []struct {
+		name   string
+		isbn13 string
+		want   string
+		wantOK bool
+	}{
+		{name: "978 prefix", isbn13: "9780306406157", want: "0306406152", wantOK: true},
+		{name: "check digit X", isbn13: "9780804429573", want: "080442957X", wantOK: true},
+		{name: "check digit X with 0 body", isbn13: "9780074625422", want: "007462542X", wantOK: true},
+		{name: "979 prefix has no isbn-10", isbn13: "9791032305690"},
+		{name: "not normalized", isbn13: "978-0-306-40615-7"},
+		{name: "empty", isbn13: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ToISBN10(tt.isbn13)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ToISBN10(%q) = %q, %v, want %q, %v", tt.isbn13, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "080442957X", "007462542X", "0000000000"} {
+		isbn13, err := Normalize(isbn10)
+		if err != nil {
+			t.Fatalf("Normalize(%q) error = %v", isbn10, err)
+		}
+		got, ok := ToISBN10(isbn13)
+		if !ok || got != isbn10 {
+			t.Errorf("ToISBN10(Normalize(%q)) = %q, %v, want %q, true", isbn10, got, ok, isbn10)
+		}
+	}
+}
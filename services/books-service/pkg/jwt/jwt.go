@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims represents the JWT claims issued by the users service
+type Claims struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+	jwt.RegisteredClaims
+}
+
+// Verifier validates tokens signed with the secret shared with the users service.
+// The books service never issues tokens itself.
+type Verifier struct {
+	secretKey string
+}
+
+// NewVerifier creates a new JWT verifier
+func NewVerifier(secretKey string) *Verifier {
+	return &Verifier{
+		secretKey: secretKey,
+	}
+}
+
+// ValidateToken validates a JWT token and returns the claims
+func (v *Verifier) ValidateToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(v.secretKey), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt == nil || time.Now().After(claims.ExpiresAt.Time) {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
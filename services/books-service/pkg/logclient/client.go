@@ -0,0 +1,95 @@
+// Package logclient writes entries to the logging service HTTP API
+package logclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Levels accepted by the logging service
+const (
+	LevelDebug = "DEBUG"
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+)
+
+// defaultTimeout bounds each request when the caller's context has no deadline
+const defaultTimeout = 5 * time.Second
+
+// Entry is a log entry as accepted by POST /api/v1/logs. Metadata is a JSON
+// document encoded as a string.
+type Entry struct {
+	ServiceName string    `json:"service_name"`
+	Level       string    `json:"level"`
+	Message     string    `json:"message"`
+	TraceID     string    `json:"trace_id,omitempty"`
+	Metadata    string    `json:"metadata,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Client writes entries to the logging service on behalf of one service
+type Client struct {
+	baseURL     string
+	serviceName string
+	http        *http.Client
+}
+
+// New creates a client for the logging service at baseURL
+// (e.g. "http://logging-service:8084") that tags entries with serviceName
+func New(baseURL, serviceName string) *Client {
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		serviceName: serviceName,
+		http:        &http.Client{},
+	}
+}
+
+// Write stores one entry. ServiceName and Timestamp default to the client's
+// service and the current time.
+func (c *Client) Write(ctx context.Context, entry Entry) error {
+	if entry.ServiceName == "" {
+		entry.ServiceName = c.serviceName
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/logs", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("logging service returned %s", resp.Status)
+	}
+	return nil
+}
+
+// withTimeout applies defaultTimeout unless ctx already has a deadline
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, defaultTimeout)
+}
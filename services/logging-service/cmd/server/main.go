@@ -146,7 +146,12 @@ func connectDB(cfg config.DatabaseConfig, log zerolog.Logger) (*gorm.DB, error)
 }
 
 func runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(&domain.Log{})
+	if err := db.AutoMigrate(&domain.Log{}); err != nil {
+		return err
+	}
+
+	// Cursor pagination walks logs by (timestamp, id)
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_logs_timestamp_id ON logs (timestamp DESC, id DESC)").Error
 }
 
 func errorHandler(c *fiber.Ctx, err error) error {
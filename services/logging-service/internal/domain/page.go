@@ -0,0 +1,21 @@
+package domain
+
+// PageRequest selects one page of logs, either by offset or by an opaque
+// cursor from a previous page. A cursor takes precedence over Offset.
+type PageRequest struct {
+	Limit        int
+	Offset       int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// LogPage is one page of logs, newest first. Total is nil when it wasn't
+// counted, and a cursor is empty when there is no page in that direction.
+type LogPage struct {
+	Logs       []Log
+	Total      *int64
+	Limit      int
+	Offset     int
+	NextCursor string
+	PrevCursor string
+}
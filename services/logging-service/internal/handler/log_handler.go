@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -42,9 +43,20 @@ func (h *LogHandler) CreateLog(c *fiber.Ctx) error {
 
 // GetLogs handles GET /api/v1/logs
 func (h *LogHandler) GetLogs(c *fiber.Ctx) error {
-	// Parse pagination
-	limit, _ := strconv.Atoi(c.Query("limit", "100"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	// Parse pagination. The total is counted by default only for offset pages.
+	page := domain.PageRequest{Cursor: c.Query("cursor")}
+	page.Limit, _ = strconv.Atoi(c.Query("limit", "100"))
+	page.Offset, _ = strconv.Atoi(c.Query("offset", "0"))
+	page.IncludeTotal = page.Cursor == ""
+	if includeTotal := c.Query("include_total"); includeTotal != "" {
+		value, err := strconv.ParseBool(includeTotal)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid include_total",
+			})
+		}
+		page.IncludeTotal = value
+	}
 
 	// Parse filters
 	filters := make(map[string]interface{})
@@ -73,17 +85,34 @@ func (h *LogHandler) GetLogs(c *fiber.Ctx) error {
 		}
 	}
 
-	logs, total, err := h.logService.GetLogs(c.Context(), filters, limit, offset)
+	result, err := h.logService.GetLogs(c.Context(), filters, page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to get logs",
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"data":   logs,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	response := fiber.Map{
+		"data":  result.Logs,
+		"limit": result.Limit,
+	}
+	if page.Cursor == "" {
+		response["offset"] = result.Offset
+	}
+	if result.Total != nil {
+		response["total"] = *result.Total
+	}
+	if result.NextCursor != "" {
+		response["next_cursor"] = result.NextCursor
+	}
+	if result.PrevCursor != "" {
+		response["prev_cursor"] = result.PrevCursor
+	}
+
+	return c.JSON(response)
 }
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,10 +13,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrInvalidCursor is returned for cursors that weren't issued by GetLogs
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	defaultLogLimit = 100
+	maxLogLimit     = 1000
+)
+
 // LogService defines the interface for logging business logic
 type LogService interface {
 	CreateLog(ctx context.Context, log *domain.Log) error
-	GetLogs(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]domain.Log, int64, error)
+	GetLogs(ctx context.Context, filters map[string]interface{}, page domain.PageRequest) (*domain.LogPage, error)
 	DeleteOldLogs(ctx context.Context, olderThan time.Duration) error
 }
 
@@ -33,13 +44,106 @@ func (s *logService) CreateLog(ctx context.Context, log *domain.Log) error {
 	return s.db.WithContext(ctx).Create(log).Error
 }
 
-func (s *logService) GetLogs(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]domain.Log, int64, error) {
+// GetLogs returns one page of logs, newest first, by offset or by cursor.
+// Cursors are keyed on (timestamp, id), which stays fast on deep pages where
+// OFFSET has to skip every earlier row. The total is only counted on request.
+func (s *logService) GetLogs(ctx context.Context, filters map[string]interface{}, page domain.PageRequest) (*domain.LogPage, error) {
+	limit, offset := page.Limit, page.Offset
+	if limit <= 0 || limit > maxLogLimit {
+		limit = defaultLogLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var position *logCursor
+	if page.Cursor != "" {
+		var err error
+		if position, err = decodeLogCursor(page.Cursor); err != nil {
+			return nil, err
+		}
+		offset = 0
+	}
+
+	result := &domain.LogPage{Limit: limit, Offset: offset}
+
+	if page.IncludeTotal {
+		var total int64
+		if err := applyLogFilters(s.db.WithContext(ctx).Model(&domain.Log{}), filters).Count(&total).Error; err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	query := applyLogFilters(s.db.WithContext(ctx).Model(&domain.Log{}), filters)
+	backwards := position != nil && position.Before
+	switch {
+	case backwards:
+		query = query.
+			Where("(timestamp, id) > (?, ?)", position.Timestamp, position.ID).
+			Order("timestamp ASC, id ASC")
+	case position != nil:
+		query = query.
+			Where("(timestamp, id) < (?, ?)", position.Timestamp, position.ID).
+			Order("timestamp DESC, id DESC")
+	default:
+		query = query.Order("timestamp DESC, id DESC").Offset(offset)
+	}
+
+	// One extra row tells whether there is another page in the direction of travel
 	var logs []domain.Log
-	var total int64
+	if err := query.Limit(limit + 1).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	more := len(logs) > limit
+	if more {
+		logs = logs[:limit]
+	}
+	if backwards {
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
+	}
+	result.Logs = logs
+	result.NextCursor, result.PrevCursor = logPageCursors(logs, position, offset, more)
+
+	return result, nil
+}
 
-	query := s.db.WithContext(ctx).Model(&domain.Log{})
+// logPageCursors returns the cursors to the older and newer pages around
+// logs, which are newest first. more reports whether the query found a row
+// beyond the page in the direction it was read.
+func logPageCursors(logs []domain.Log, position *logCursor, offset int, more bool) (next, prev string) {
+	if len(logs) == 0 {
+		return "", ""
+	}
 
-	// Apply filters
+	backwards := position != nil && position.Before
+	if more || backwards {
+		next = encodeLogCursor(logCursor{Timestamp: logs[len(logs)-1].Timestamp, ID: logs[len(logs)-1].ID})
+	}
+	if (more && backwards) || (!backwards && (position != nil || offset > 0)) {
+		prev = encodeLogCursor(logCursor{Timestamp: logs[0].Timestamp, ID: logs[0].ID, Before: true})
+	}
+	return next, prev
+}
+
+func (s *logService) DeleteOldLogs(ctx context.Context, olderThan time.Duration) error {
+	cutoffTime := time.Now().UTC().Add(-olderThan)
+	result := s.db.WithContext(ctx).
+		Where("timestamp < ?", cutoffTime).
+		Delete(&domain.Log{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete old logs: %w", result.Error)
+	}
+
+	return nil
+}
+
+// applyLogFilters adds the WHERE clauses for the supported log filters
+func applyLogFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
 	if serviceName, ok := filters["service_name"]; ok {
 		query = query.Where("service_name = ?", serviceName)
 	}
@@ -64,31 +168,30 @@ func (s *logService) GetLogs(ctx context.Context, filters map[string]interface{}
 			query = query.Where("timestamp <= ?", t)
 		}
 	}
+	return query
+}
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Apply pagination and ordering
-	err := query.
-		Order("timestamp DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&logs).Error
-
-	return logs, total, err
+// logCursor is the position a cursor page continues from. Before cursors
+// walk back towards newer logs.
+type logCursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Before    bool      `json:"b,omitempty"`
 }
 
-func (s *logService) DeleteOldLogs(ctx context.Context, olderThan time.Duration) error {
-	cutoffTime := time.Now().UTC().Add(-olderThan)
-	result := s.db.WithContext(ctx).
-		Where("timestamp < ?", cutoffTime).
-		Delete(&domain.Log{})
+func encodeLogCursor(cursor logCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete old logs: %w", result.Error)
+func decodeLogCursor(value string) (*logCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
 	}
-
-	return nil
+	var cursor logCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil || cursor.Timestamp.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
 }
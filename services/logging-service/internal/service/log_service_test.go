@@ -0,0 +1,246 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/bookstore/services/logging-service/internal/domain"
+)
+
+func TestLogCursorRoundTrip(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name   string
+		cursor logCursor
+	}{
+		{name: "after", cursor: logCursor{Timestamp: time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: id}},
+		{name: "before", cursor: logCursor{Timestamp: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC), ID: id, Before: true}},
+		{name: "nanoseconds", cursor: logCursor{Timestamp: time.Date(2026, 3, 1, 12, 30, 0, 999999999, time.UTC), ID: id}},
+		{name: "other zone", cursor: logCursor{Timestamp: time.Date(2026, 3, 1, 7, 30, 0, 0, time.FixedZone("EST", -5*3600)), ID: id}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodeLogCursor(tt.cursor)
+			decoded, err := decodeLogCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeLogCursor(%q) error = %v", encoded, err)
+			}
+			if !decoded.Timestamp.Equal(tt.cursor.Timestamp) || decoded.ID != tt.cursor.ID || decoded.Before != tt.cursor.Before {
+				t.Errorf("decodeLogCursor(encodeLogCursor(%+v)) = %+v", tt.cursor, *decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeLogCursorInvalid(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	id := uuid.New().String()
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "empty", value: ""},
+		{name: "not base64", value: "not a cursor!"},
+		{name: "not json", value: encode("cursor")},
+		{name: "json array", value: encode(`["2026-03-01T12:30:00Z","` + id + `"]`)},
+		{name: "missing id", value: encode(`{"t":"2026-03-01T12:30:00Z"}`)},
+		{name: "nil id", value: encode(`{"t":"2026-03-01T12:30:00Z","id":"` + uuid.Nil.String() + `"}`)},
+		{name: "malformed id", value: encode(`{"t":"2026-03-01T12:30:00Z","id":"42"}`)},
+		{name: "missing timestamp", value: encode(`{"id":"` + id + `"}`)},
+		{name: "malformed timestamp", value: encode(`{"t":"yesterday","id":"` + id + `"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeLogCursor(tt.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeLogCursor(%q) error = %v, want ErrInvalidCursor", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestLogPageCursors(t *testing.T) {
+	logs := testLogs(3)
+	first, last := logs[0], logs[len(logs)-1]
+	after := &logCursor{Timestamp: first.Timestamp.Add(time.Second), ID: uuid.New()}
+	before := &logCursor{Timestamp: last.Timestamp.Add(-time.Second), ID: uuid.New(), Before: true}
+
+	tests := []struct {
+		name     string
+		logs     []domain.Log
+		position *logCursor
+		offset   int
+		more     bool
+		wantNext bool
+		wantPrev bool
+	}{
+		{name: "only page", logs: logs},
+		{name: "first page", logs: logs, more: true, wantNext: true},
+		{name: "middle page by offset", logs: logs, offset: 3, more: true, wantNext: true, wantPrev: true},
+		{name: "last page by offset", logs: logs, offset: 3, wantPrev: true},
+		{name: "middle page after a cursor", logs: logs, position: after, more: true, wantNext: true, wantPrev: true},
+		{name: "oldest page after a cursor", logs: logs, position: after, wantPrev: true},
+		{name: "middle page before a cursor", logs: logs, position: before, more: true, wantNext: true, wantPrev: true},
+		{name: "newest page before a cursor", logs: logs, position: before, wantNext: true},
+		{name: "empty page", logs: nil, position: after},
+		{name: "empty page past the offset", logs: nil, offset: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, prev := logPageCursors(tt.logs, tt.position, tt.offset, tt.more)
+
+			if (next != "") != tt.wantNext {
+				t.Fatalf("next cursor = %q, want one: %v", next, tt.wantNext)
+			}
+			if next != "" {
+				cursor, err := decodeLogCursor(next)
+				if err != nil {
+					t.Fatalf("next cursor: %v", err)
+				}
+				if cursor.Before || cursor.ID != last.ID || !cursor.Timestamp.Equal(last.Timestamp) {
+					t.Errorf("next cursor = %+v, want after the oldest log %s", *cursor, last.ID)
+				}
+			}
+
+			if (prev != "") != tt.wantPrev {
+				t.Fatalf("prev cursor = %q, want one: %v", prev, tt.wantPrev)
+			}
+			if prev != "" {
+				cursor, err := decodeLogCursor(prev)
+				if err != nil {
+					t.Fatalf("prev cursor: %v", err)
+				}
+				if !cursor.Before || cursor.ID != first.ID || !cursor.Timestamp.Equal(first.Timestamp) {
+					t.Errorf("prev cursor = %+v, want before the newest log %s", *cursor, first.ID)
+				}
+			}
+		})
+	}
+}
+
+// TestLogCursorWalk pages through logs to the oldest end and back to the
+// newest, following the cursors as a client would
+func TestLogCursorWalk(t *testing.T) {
+	all := testLogs(7)
+	// Two logs share a timestamp, so the ID has to break the tie
+	all[4].Timestamp = all[3].Timestamp
+	sortLogs(all)
+	const limit = 3
+
+	page := readLogPage(t, all, "", limit)
+	var seen []domain.Log
+	for pages := 1; ; pages++ {
+		seen = append(seen, page.Logs...)
+		if page.NextCursor == "" {
+			break
+		}
+		if pages > len(all) {
+			t.Fatal("next cursors never reached the oldest log")
+		}
+		page = readLogPage(t, all, page.NextCursor, limit)
+	}
+	assertLogIDs(t, "forwards", seen, all)
+
+	seen = append([]domain.Log(nil), page.Logs...)
+	for pages := 1; page.PrevCursor != ""; pages++ {
+		if pages > len(all) {
+			t.Fatal("prev cursors never reached the newest log")
+		}
+		page = readLogPage(t, all, page.PrevCursor, limit)
+		seen = append(append([]domain.Log(nil), page.Logs...), seen...)
+	}
+	assertLogIDs(t, "backwards", seen, all)
+}
+
+// readLogPage reads a page of all, which is newest first, the way GetLogs
+// queries the table
+func readLogPage(t *testing.T, all []domain.Log, cursor string, limit int) *domain.LogPage {
+	t.Helper()
+
+	var position *logCursor
+	if cursor != "" {
+		var err error
+		if position, err = decodeLogCursor(cursor); err != nil {
+			t.Fatalf("decodeLogCursor(%q) error = %v", cursor, err)
+		}
+	}
+
+	var logs []domain.Log
+	switch {
+	case position != nil && position.Before:
+		for i := len(all) - 1; i >= 0; i-- {
+			if compareLog(all[i], position) > 0 {
+				logs = append(logs, all[i])
+			}
+		}
+	case position != nil:
+		for _, log := range all {
+			if compareLog(log, position) < 0 {
+				logs = append(logs, log)
+			}
+		}
+	default:
+		logs = append(logs, all...)
+	}
+
+	more := len(logs) > limit
+	if more {
+		logs = logs[:limit]
+	}
+	if position != nil && position.Before {
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
+	}
+
+	page := &domain.LogPage{Logs: logs, Limit: limit}
+	page.NextCursor, page.PrevCursor = logPageCursors(logs, position, 0, more)
+	return page
+}
+
+// compareLog compares the (timestamp, id) of log with the cursor's position
+func compareLog(log domain.Log, cursor *logCursor) int {
+	if !log.Timestamp.Equal(cursor.Timestamp) {
+		return log.Timestamp.Compare(cursor.Timestamp)
+	}
+	return strings.Compare(log.ID.String(), cursor.ID.String())
+}
+
+// testLogs returns n logs a minute apart, newest first
+func testLogs(n int) []domain.Log {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	logs := make([]domain.Log, n)
+	for i := range logs {
+		logs[i] = domain.Log{ID: uuid.New(), Timestamp: start.Add(-time.Duration(i) * time.Minute)}
+	}
+	return logs
+}
+
+// sortLogs orders logs by (timestamp, id) descending, as GetLogs does
+func sortLogs(logs []domain.Log) {
+	sort.Slice(logs, func(i, j int) bool {
+		return compareLog(logs[i], &logCursor{Timestamp: logs[j].Timestamp, ID: logs[j].ID}) > 0
+	})
+}
+
+func assertLogIDs(t *testing.T, direction string, got, want []domain.Log) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: read %d logs, want %d", direction, len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Fatalf("%s: log %d = %s, want %s", direction, i, got[i].ID, want[i].ID)
+		}
+	}
+}
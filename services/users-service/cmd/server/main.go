@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,11 +14,16 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/config"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/grpcserver"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/handler"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/middleware"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository/postgres"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
 	customJWT "github.com/youngermaster/my-distributed-bookstore/services/users-service/pkg/jwt"
+	authv1 "github.com/youngermaster/my-distributed-bookstore/services/users-service/proto/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	postgresql "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -49,6 +56,9 @@ func main() {
 	if err := service.SeedRoles(db); err != nil {
 		log.Fatal().Err(err).Msg("Failed to seed roles")
 	}
+	if err := service.GrantRolePermissions(db); err != nil {
+		log.Fatal().Err(err).Msg("Failed to grant role permissions")
+	}
 
 	// Initialize JWT manager
 	jwtManager := customJWT.NewJWTManager(cfg.JWT.Secret, cfg.JWT.GetTokenDuration())
@@ -56,14 +66,41 @@ func main() {
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
 	wishlistRepo := postgres.NewWishlistRepository(db)
+	roleRepo := postgres.NewRoleRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+	addressRepo := postgres.NewAddressRepository(db)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, jwtManager)
+	authService := service.NewAuthService(
+		userRepo,
+		sessionRepo,
+		roleRepo,
+		jwtManager,
+		cfg.JWT.GetRefreshTokenDuration(),
+		cfg.RBAC.DefaultRoles,
+	)
 	wishlistService := service.NewWishlistService(wishlistRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	addressService := service.NewAddressService(addressRepo)
+	userService := service.NewUserService(userRepo, sessionRepo)
+
+	// Periodically purge expired refresh sessions
+	cleanupTicker := time.NewTicker(time.Hour)
+	defer cleanupTicker.Stop()
+	go func() {
+		for range cleanupTicker.C {
+			if err := sessionRepo.DeleteExpired(context.Background()); err != nil {
+				log.Error().Err(err).Msg("Failed to delete expired sessions")
+			}
+		}
+	}()
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	wishlistHandler := handler.NewWishlistHandler(wishlistService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	addressHandler := handler.NewAddressHandler(addressService)
+	userHandler := handler.NewUserHandler(userService)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -114,19 +151,36 @@ func main() {
 
 	// User profile routes (protected)
 	users := api.Group("/users", middleware.AuthMiddleware(authService))
-	users.Get("/me", func(c *fiber.Ctx) error {
-		userID := c.Locals("userID")
-		userEmail := c.Locals("userEmail")
-		return c.JSON(fiber.Map{
-			"id":    userID,
-			"email": userEmail,
-		})
-	})
+	canReadProfile := middleware.RequirePermission("profile:read")
+	canWriteProfile := middleware.RequirePermission("profile:write")
+	users.Get("/me", canReadProfile, userHandler.GetProfile)
+	users.Patch("/me", canWriteProfile, userHandler.UpdateProfile)
+	users.Post("/me/password", canWriteProfile, userHandler.ChangePassword)
 
 	// Wishlist routes (protected)
-	users.Get("/me/wishlist", wishlistHandler.GetWishlist)
-	users.Post("/me/wishlist", wishlistHandler.AddToWishlist)
-	users.Delete("/me/wishlist/:book_id", wishlistHandler.RemoveFromWishlist)
+	users.Get("/me/wishlist", middleware.RequirePermission("wishlist:read"), wishlistHandler.GetWishlist)
+	users.Post("/me/wishlist", middleware.RequirePermission("wishlist:write"), wishlistHandler.AddToWishlist)
+	users.Delete("/me/wishlist/:book_id", middleware.RequirePermission("wishlist:write"), wishlistHandler.RemoveFromWishlist)
+
+	// Address book routes (protected)
+	users.Get("/me/addresses", canReadProfile, addressHandler.ListAddresses)
+	users.Post("/me/addresses", canWriteProfile, addressHandler.CreateAddress)
+	users.Get("/me/addresses/:id", canReadProfile, addressHandler.GetAddress)
+	users.Put("/me/addresses/:id", canWriteProfile, addressHandler.UpdateAddress)
+	users.Delete("/me/addresses/:id", canWriteProfile, addressHandler.DeleteAddress)
+	users.Post("/me/addresses/:id/default", canWriteProfile, addressHandler.SetDefaultAddress)
+
+	// User role assignment (admin only)
+	users.Post("/:id/roles", middleware.RequirePermission("users:write"), roleHandler.GrantRole)
+	users.Delete("/:id/roles/:role_id", middleware.RequirePermission("users:write"), roleHandler.RevokeRole)
+
+	// Role administration routes (admin only)
+	roles := api.Group("/roles", middleware.AuthMiddleware(authService), middleware.RequirePermission("users:write"))
+	roles.Get("/", roleHandler.ListRoles)
+	roles.Post("/", roleHandler.CreateRole)
+	roles.Get("/:id", roleHandler.GetRole)
+	roles.Put("/:id/permissions", roleHandler.UpdatePermissions)
+	roles.Delete("/:id", roleHandler.DeleteRole)
 
 	// Start server in a goroutine
 	go func() {
@@ -137,12 +191,37 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server in a goroutine
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcserver.Logger(log),
+			grpcserver.Auth(authService, cfg.GRPC.ServiceToken),
+		),
+	)
+	authv1.RegisterAuthServer(grpcServer, grpcserver.NewAuthServer(authService, userRepo))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		addr := fmt.Sprintf(":%s", cfg.Server.GRPCPort)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to listen for gRPC")
+		}
+		log.Info().Str("addr", addr).Msg("Users gRPC server listening")
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start gRPC server")
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
 	log.Info().Msg("Shutting down server...")
+	healthServer.Shutdown()
+	grpcServer.GracefulStop()
 	if err := app.Shutdown(); err != nil {
 		log.Error().Err(err).Msg("Server shutdown error")
 	}
@@ -179,14 +258,20 @@ func connectDB(cfg config.DatabaseConfig, log zerolog.Logger) (*gorm.DB, error)
 }
 
 func runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&domain.User{},
 		&domain.Role{},
 		&domain.UserRole{},
 		&domain.Address{},
 		&domain.Session{},
 		&domain.WishlistItem{},
-	)
+	); err != nil {
+		return err
+	}
+
+	// At most one default address per user and address type
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_one_default
+		ON addresses (user_id, type) WHERE is_default`).Error
 }
 
 func errorHandler(c *fiber.Ctx, err error) error {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,8 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	RBAC     RBACConfig
+	GRPC     GRPCConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -33,8 +36,20 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret             string
+	AccessTokenMinutes int
+	RefreshTokenHours  int
+}
+
+// RBACConfig holds role-based access control configuration
+type RBACConfig struct {
+	DefaultRoles []string
+}
+
+// GRPCConfig holds the token other services may present instead of a user's
+// access token when calling restricted gRPC methods. Empty disables it.
+type GRPCConfig struct {
+	ServiceToken string
 }
 
 // Load loads configuration from environment variables
@@ -54,8 +69,15 @@ func Load() *Config {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "dev_jwt_secret_change_in_production"),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Secret:             getEnv("JWT_SECRET", "dev_jwt_secret_change_in_production"),
+			AccessTokenMinutes: getEnvAsInt("JWT_ACCESS_TOKEN_MINUTES", 15),
+			RefreshTokenHours:  getEnvAsInt("JWT_REFRESH_TOKEN_HOURS", 720),
+		},
+		RBAC: RBACConfig{
+			DefaultRoles: getEnvAsSlice("DEFAULT_ROLES", []string{"customer"}),
+		},
+		GRPC: GRPCConfig{
+			ServiceToken: getEnv("GRPC_SERVICE_TOKEN", ""),
 		},
 	}
 }
@@ -68,9 +90,14 @@ func (c *DatabaseConfig) GetDSN() string {
 	)
 }
 
-// GetTokenDuration returns the JWT token duration
+// GetTokenDuration returns the access token duration
 func (c *JWTConfig) GetTokenDuration() time.Duration {
-	return time.Duration(c.ExpirationHours) * time.Hour
+	return time.Duration(c.AccessTokenMinutes) * time.Minute
+}
+
+// GetRefreshTokenDuration returns the refresh token duration
+func (c *JWTConfig) GetRefreshTokenDuration() time.Duration {
+	return time.Duration(c.RefreshTokenHours) * time.Hour
 }
 
 // getEnv gets an environment variable with a fallback default value
@@ -90,3 +117,18 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsSlice gets a comma-separated environment variable as a slice with a fallback default value
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,9 +1,17 @@
 package domain
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 )
 
+// Built-in role names created by SeedRoles
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
 // Role represents a user role
 type Role struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -16,6 +24,18 @@ func (Role) TableName() string {
 	return "roles"
 }
 
+// PermissionList decodes the JSON permissions array stored on the role
+func (r *Role) PermissionList() []string {
+	permissions := make([]string, 0)
+	if r.Permissions == "" {
+		return permissions
+	}
+	if err := json.Unmarshal([]byte(r.Permissions), &permissions); err != nil {
+		return make([]string, 0)
+	}
+	return permissions
+}
+
 // UserRole represents the many-to-many relationship between users and roles
 type UserRole struct {
 	UserID uuid.UUID `gorm:"type:uuid;primaryKey"`
@@ -6,13 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
-// Session represents a user session
+// Session represents a refresh token issued to a user.
+// Rotated tokens share a FamilyID so reuse of an old token can revoke the whole chain.
 type Session struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	TokenHash string    `json:"-" gorm:"uniqueIndex;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	FamilyID  uuid.UUID  `json:"family_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 // TableName specifies the table name for Session
@@ -24,3 +27,8 @@ func (Session) TableName() string {
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
+
+// IsRevoked checks if the session has been revoked by logout or rotation
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
@@ -0,0 +1,117 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
+	authv1 "github.com/youngermaster/my-distributed-bookstore/services/users-service/proto/auth/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// AuthServer implements the Auth gRPC service used by other services to
+// validate access tokens
+type AuthServer struct {
+	authv1.UnimplementedAuthServer
+	authService service.AuthService
+	userRepo    repository.UserRepository
+}
+
+// NewAuthServer creates a new instance of AuthServer
+func NewAuthServer(authService service.AuthService, userRepo repository.UserRepository) *AuthServer {
+	return &AuthServer{
+		authService: authService,
+		userRepo:    userRepo,
+	}
+}
+
+// ValidateToken handles Auth.ValidateToken
+func (s *AuthServer) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	claims, err := s.authService.ValidateToken(ctx, stripBearer(req.GetToken()))
+	if err != nil {
+		return nil, tokenError(err)
+	}
+
+	resp := &authv1.ValidateTokenResponse{
+		UserId:      claims.UserID.String(),
+		SessionId:   claims.SessionID.String(),
+		Email:       claims.Email,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = timestamppb.New(claims.ExpiresAt.Time)
+	}
+	return resp, nil
+}
+
+// GetUser handles Auth.GetUser
+func (s *AuthServer) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.User, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get user")
+	}
+
+	roles, permissions := service.ResolveRoles(user.Roles)
+	return &authv1.User{
+		Id:          user.ID.String(),
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Roles:       roles,
+		Permissions: permissions,
+		CreatedAt:   timestamppb.New(user.CreatedAt),
+	}, nil
+}
+
+// CheckPermission handles Auth.CheckPermission. An invalid token is an error;
+// a valid token lacking the permission is reported with allowed=false.
+func (s *AuthServer) CheckPermission(ctx context.Context, req *authv1.CheckPermissionRequest) (*authv1.CheckPermissionResponse, error) {
+	if req.GetPermission() == "" {
+		return nil, status.Error(codes.InvalidArgument, "permission is required")
+	}
+
+	claims, err := s.authService.ValidateToken(ctx, stripBearer(req.GetToken()))
+	if err != nil {
+		return nil, tokenError(err)
+	}
+
+	allowed := false
+	for _, granted := range claims.Permissions {
+		if granted == req.GetPermission() {
+			allowed = true
+			break
+		}
+	}
+
+	return &authv1.CheckPermissionResponse{
+		Allowed: allowed,
+		UserId:  claims.UserID.String(),
+	}, nil
+}
+
+// tokenError maps token validation errors to gRPC status errors
+func tokenError(err error) error {
+	if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrSessionRevoked) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to validate token")
+}
+
+// stripBearer accepts tokens with or without the "Bearer " prefix
+func stripBearer(token string) string {
+	return strings.TrimPrefix(strings.TrimSpace(token), "Bearer ")
+}
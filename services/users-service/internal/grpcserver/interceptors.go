@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
+	authv1 "github.com/youngermaster/my-distributed-bookstore/services/users-service/proto/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodPermissions lists the RPCs that require a token and the permission they need.
+// Methods not listed are public: checking a token only needs the token itself.
+var methodPermissions = map[string]string{
+	authv1.Auth_GetUser_FullMethodName: "users:read",
+}
+
+// Logger creates an interceptor that logs unary RPCs
+func Logger(log zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		log.Info().
+			Str("method", info.FullMethod).
+			Str("code", status.Code(err).String()).
+			Dur("duration", time.Since(start)).
+			Msg("gRPC request")
+
+		return resp, err
+	}
+}
+
+// Auth creates an interceptor that checks the bearer token in the
+// "authorization" metadata for methods listed in methodPermissions. The token
+// is either the shared service token, when one is configured, or an access
+// token granting the method's permission.
+func Auth(authService service.AuthService, serviceToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		required, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "no authorization metadata")
+		}
+
+		tokenString := strings.TrimPrefix(values[0], "Bearer ")
+		if tokenString == values[0] {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization format")
+		}
+
+		if serviceToken != "" && subtle.ConstantTimeCompare([]byte(tokenString), []byte(serviceToken)) == 1 {
+			return handler(ctx, req)
+		}
+
+		claims, err := authService.ValidateToken(ctx, tokenString)
+		if err != nil {
+			return nil, tokenError(err)
+		}
+
+		for _, granted := range claims.Permissions {
+			if granted == required {
+				return handler(ctx, req)
+			}
+		}
+
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
+)
+
+// AddressHandler handles HTTP requests for the authenticated user's address book
+type AddressHandler struct {
+	addressService service.AddressService
+}
+
+// NewAddressHandler creates a new instance of AddressHandler
+func NewAddressHandler(addressService service.AddressService) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+	}
+}
+
+// ListAddresses handles GET /api/v1/users/me/addresses
+func (h *AddressHandler) ListAddresses(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	addresses, err := h.addressService.ListAddresses(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list addresses",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  addresses,
+		"total": len(addresses),
+	})
+}
+
+// GetAddress handles GET /api/v1/users/me/addresses/:id
+func (h *AddressHandler) GetAddress(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	address, err := h.addressService.GetAddress(c.Context(), userID, addressID)
+	if err != nil {
+		return addressError(c, err, "Failed to get address")
+	}
+
+	return c.JSON(address)
+}
+
+// CreateAddress handles POST /api/v1/users/me/addresses
+func (h *AddressHandler) CreateAddress(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var address domain.Address
+	if err := c.BodyParser(&address); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.addressService.CreateAddress(c.Context(), userID, &address); err != nil {
+		return addressError(c, err, "Failed to create address")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(address)
+}
+
+// UpdateAddress handles PUT /api/v1/users/me/addresses/:id
+func (h *AddressHandler) UpdateAddress(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	var address domain.Address
+	if err := c.BodyParser(&address); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	address.ID = addressID
+
+	if err := h.addressService.UpdateAddress(c.Context(), userID, &address); err != nil {
+		return addressError(c, err, "Failed to update address")
+	}
+
+	return c.JSON(address)
+}
+
+// DeleteAddress handles DELETE /api/v1/users/me/addresses/:id
+func (h *AddressHandler) DeleteAddress(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	if err := h.addressService.DeleteAddress(c.Context(), userID, addressID); err != nil {
+		return addressError(c, err, "Failed to delete address")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetDefaultAddress handles POST /api/v1/users/me/addresses/:id/default
+func (h *AddressHandler) SetDefaultAddress(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid address ID",
+		})
+	}
+
+	address, err := h.addressService.SetDefaultAddress(c.Context(), userID, addressID)
+	if err != nil {
+		return addressError(c, err, "Failed to set default address")
+	}
+
+	return c.JSON(address)
+}
+
+// addressError maps address service errors to HTTP responses
+func addressError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Address not found",
+		})
+	case errors.Is(err, service.ErrInvalidInput),
+		errors.Is(err, service.ErrInvalidAddressType),
+		errors.Is(err, service.ErrInvalidCountry),
+		errors.Is(err, service.ErrInvalidPostalCode):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -2,9 +2,9 @@ package handler
 
 import (
 	"errors"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
 )
 
@@ -70,7 +70,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	token, user, err := h.authService.Login(c.Context(), req.Email, req.Password)
+	tokens, user, err := h.authService.Login(c.Context(), req.Email, req.Password)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -83,20 +83,30 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"token": token,
-		"user":  user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(tokens.ExpiresIn.Seconds()),
+		"user":          user,
 	})
 }
 
 // Logout handles POST /api/v1/auth/logout
+// Revokes the current session, or every session of the user with ?all=true.
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
-	// Extract user ID from context (set by auth middleware)
-	userID := c.Locals("userID")
-	if userID == nil {
+	// Extract user and session from context (set by auth middleware)
+	userID, ok := currentUserID(c)
+	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Unauthorized",
 		})
 	}
+	sessionID, _ := c.Locals("sessionID").(uuid.UUID)
+
+	if err := h.authService.Logout(c.Context(), userID, sessionID, c.QueryBool("all", false)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to logout",
+		})
+	}
 
 	return c.JSON(fiber.Map{
 		"message": "Logged out successfully",
@@ -105,22 +115,37 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 
 // RefreshToken handles POST /api/v1/auth/refresh
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "No token provided",
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No refresh token provided",
 		})
 	}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	newToken, err := h.authService.RefreshToken(c.Context(), tokenString)
+	tokens, err := h.authService.RefreshToken(c.Context(), req.RefreshToken)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid or expired token",
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired refresh token",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh token",
 		})
 	}
 
 	return c.JSON(fiber.Map{
-		"token": newToken,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(tokens.ExpiresIn.Seconds()),
 	})
 }
+
+// currentUserID returns the authenticated user's ID stored by AuthMiddleware
+func currentUserID(c *fiber.Ctx) (uuid.UUID, bool) {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	return userID, ok && userID != uuid.Nil
+}
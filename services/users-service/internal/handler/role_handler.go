@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
+)
+
+// RoleHandler handles HTTP requests for role administration
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+// NewRoleHandler creates a new instance of RoleHandler
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// ListRoles handles GET /api/v1/roles
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.roleService.ListRoles(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list roles",
+		})
+	}
+
+	data := make([]fiber.Map, 0, len(roles))
+	for i := range roles {
+		data = append(data, roleResponse(&roles[i]))
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  data,
+		"total": len(data),
+	})
+}
+
+// GetRole handles GET /api/v1/roles/:id
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	role, err := h.roleService.GetRole(c.Context(), id)
+	if err != nil {
+		return roleError(c, err, "Failed to get role")
+	}
+
+	return c.JSON(roleResponse(role))
+}
+
+// CreateRole handles POST /api/v1/roles
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req struct {
+		Name        string   `json:"name"`
+		Permissions []string `json:"permissions"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	role, err := h.roleService.CreateRole(c.Context(), req.Name, req.Permissions)
+	if err != nil {
+		return roleError(c, err, "Failed to create role")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(roleResponse(role))
+}
+
+// UpdatePermissions handles PUT /api/v1/roles/:id/permissions
+func (h *RoleHandler) UpdatePermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	var req struct {
+		Permissions []string `json:"permissions"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	role, err := h.roleService.UpdatePermissions(c.Context(), id, req.Permissions)
+	if err != nil {
+		return roleError(c, err, "Failed to update role")
+	}
+
+	return c.JSON(roleResponse(role))
+}
+
+// DeleteRole handles DELETE /api/v1/roles/:id
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	if err := h.roleService.DeleteRole(c.Context(), id); err != nil {
+		return roleError(c, err, "Failed to delete role")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GrantRole handles POST /api/v1/users/:id/roles
+func (h *RoleHandler) GrantRole(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req struct {
+		RoleID string `json:"role_id"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	user, err := h.roleService.GrantRole(c.Context(), userID, roleID)
+	if err != nil {
+		return roleError(c, err, "Failed to grant role")
+	}
+
+	return c.JSON(user)
+}
+
+// RevokeRole handles DELETE /api/v1/users/:id/roles/:role_id
+func (h *RoleHandler) RevokeRole(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	roleID, err := uuid.Parse(c.Params("role_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role ID",
+		})
+	}
+
+	user, err := h.roleService.RevokeRole(c.Context(), userID, roleID)
+	if err != nil {
+		return roleError(c, err, "Failed to revoke role")
+	}
+
+	return c.JSON(user)
+}
+
+// roleResponse exposes the stored JSON permissions as an array
+func roleResponse(role *domain.Role) fiber.Map {
+	return fiber.Map{
+		"id":          role.ID,
+		"name":        role.Name,
+		"permissions": role.PermissionList(),
+	}
+}
+
+// roleError maps role service errors to HTTP responses
+func roleError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Role not found",
+		})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	case errors.Is(err, service.ErrRoleAlreadyExists),
+		errors.Is(err, service.ErrBuiltinRole),
+		errors.Is(err, service.ErrRoleAlreadyGranted):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidPermission):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/service"
+)
+
+// UserHandler handles HTTP requests for the authenticated user's profile
+type UserHandler struct {
+	userService service.UserService
+}
+
+// NewUserHandler creates a new instance of UserHandler
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// GetProfile handles GET /api/v1/users/me
+func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	user, err := h.userService.GetProfile(c.Context(), userID)
+	if err != nil {
+		return userError(c, err, "Failed to get profile")
+	}
+
+	return c.JSON(user)
+}
+
+// UpdateProfile handles PATCH /api/v1/users/me
+func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req struct {
+		FullName *string `json:"full_name"`
+		Email    *string `json:"email"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.userService.UpdateProfile(c.Context(), userID, service.ProfileUpdate{
+		FullName: req.FullName,
+		Email:    req.Email,
+	})
+	if err != nil {
+		return userError(c, err, "Failed to update profile")
+	}
+
+	return c.JSON(user)
+}
+
+// ChangePassword handles POST /api/v1/users/me/password
+// Every other session of the user is revoked on success.
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	sessionID, _ := c.Locals("sessionID").(uuid.UUID)
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.userService.ChangePassword(c.Context(), userID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		return userError(c, err, "Failed to change password")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password changed successfully",
+	})
+}
+
+// userError maps user service errors to HTTP responses
+func userError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	case errors.Is(err, service.ErrUserAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "User with this email already exists",
+		})
+	case errors.Is(err, service.ErrIncorrectPassword):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidInput),
+		errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrInvalidFullName),
+		errors.Is(err, service.ErrEmptyProfileUpdate),
+		errors.Is(err, service.ErrPasswordUnchanged):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
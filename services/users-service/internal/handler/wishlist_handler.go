@@ -29,11 +29,10 @@ func NewWishlistHandler(wishlistService *service.WishlistService) *WishlistHandl
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/users/me/wishlist [get]
 func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-	uid, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
+	uid, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
 		})
 	}
 
@@ -63,11 +62,10 @@ func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/users/me/wishlist [post]
 func (h *WishlistHandler) AddToWishlist(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-	uid, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
+	uid, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
 		})
 	}
 
@@ -118,11 +116,10 @@ func (h *WishlistHandler) AddToWishlist(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/users/me/wishlist/{book_id} [delete]
 func (h *WishlistHandler) RemoveFromWishlist(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-	uid, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
+	uid, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
 		})
 	}
 
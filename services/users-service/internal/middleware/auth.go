@@ -35,8 +35,10 @@ func AuthMiddleware(authService service.AuthService) fiber.Handler {
 
 		// Store user info in context
 		c.Locals("userID", claims.UserID)
+		c.Locals("sessionID", claims.SessionID)
 		c.Locals("userEmail", claims.Email)
 		c.Locals("userRoles", claims.Roles)
+		c.Locals("userPermissions", claims.Permissions)
 
 		return c.Next()
 	}
@@ -66,3 +68,30 @@ func RequireRole(roles ...string) fiber.Handler {
 		})
 	}
 }
+
+// RequirePermission creates a middleware that checks if user holds any of the
+// required permissions. Permissions are resolved from the user's roles when
+// the token is issued.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userPermissions, ok := c.Locals("userPermissions").([]string)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied",
+			})
+		}
+
+		// Check if user has any of the required permissions
+		for _, requiredPermission := range permissions {
+			for _, userPermission := range userPermissions {
+				if userPermission == requiredPermission {
+					return c.Next()
+				}
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Insufficient permissions",
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressRepository creates a new instance of AddressRepository
+func NewAddressRepository(db *gorm.DB) repository.AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// Create inserts the address, clearing any other default of the same type first
+func (r *addressRepository) Create(ctx context.Context, address *domain.Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, address.Type, uuid.Nil); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+func (r *addressRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Address, error) {
+	var address domain.Address
+	err := r.db.WithContext(ctx).First(&address, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *addressRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Address, error) {
+	var addresses []domain.Address
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+// Update saves the address, clearing any other default of the same type first
+func (r *addressRepository) Update(ctx context.Context, address *domain.Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, address.Type, address.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(address).Error
+	})
+}
+
+func (r *addressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.Address{}, "id = ?", id).Error
+}
+
+// SetDefault makes the address the user's only default of its type
+func (r *addressRepository) SetDefault(ctx context.Context, userID, addressID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var address domain.Address
+		if err := tx.First(&address, "id = ? AND user_id = ?", addressID, userID).Error; err != nil {
+			return err
+		}
+		if err := clearDefaultAddress(tx, userID, address.Type, addressID); err != nil {
+			return err
+		}
+		return tx.Model(&domain.Address{}).
+			Where("id = ?", addressID).
+			Update("is_default", true).Error
+	})
+}
+
+// clearDefaultAddress unsets the default flag on the user's other addresses of the given type.
+// The user row is locked so concurrent default changes for the same user serialize.
+func clearDefaultAddress(tx *gorm.DB, userID uuid.UUID, addressType string, exceptID uuid.UUID) error {
+	var user domain.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&domain.Address{}).
+		Where("user_id = ? AND type = ? AND is_default AND id <> ?", userID, addressType, exceptID).
+		Update("is_default", false).Error
+}
@@ -61,3 +61,16 @@ func (r *userRepository) AssignRole(ctx context.Context, userID, roleID uuid.UUI
 	}
 	return r.db.WithContext(ctx).Create(&userRole).Error
 }
+
+func (r *userRepository) RevokeRole(ctx context.Context, userID, roleID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND role_id = ?", userID, roleID).
+		Delete(&domain.UserRole{}).Error
+}
+
+// Transaction runs fn with a repository bound to a single database transaction
+func (r *userRepository) Transaction(ctx context.Context, fn func(txRepo repository.UserRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&userRepository{db: tx})
+	})
+}
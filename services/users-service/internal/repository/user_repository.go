@@ -15,6 +15,18 @@ type UserRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	AssignRole(ctx context.Context, userID, roleID uuid.UUID) error
+	RevokeRole(ctx context.Context, userID, roleID uuid.UUID) error
+	Transaction(ctx context.Context, fn func(txRepo UserRepository) error) error
+}
+
+// RoleRepository defines the interface for role data access
+type RoleRepository interface {
+	Create(ctx context.Context, role *domain.Role) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Role, error)
+	FindByName(ctx context.Context, name string) (*domain.Role, error)
+	FindAll(ctx context.Context) ([]domain.Role, error)
+	Update(ctx context.Context, role *domain.Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 // AddressRepository defines the interface for address data access
@@ -30,7 +42,12 @@ type AddressRepository interface {
 // SessionRepository defines the interface for session data access
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Session, error)
 	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error)
+	Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
+	RevokeOtherFamilies(ctx context.Context, userID, keepFamilyID uuid.UUID) error
 	DeleteExpired(ctx context.Context) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAddressNotFound    = errors.New("address not found")
+	ErrInvalidAddressType = errors.New("address type must be shipping or billing")
+	ErrInvalidCountry     = errors.New("country must be an ISO 3166-1 alpha-2 code")
+	ErrInvalidPostalCode  = errors.New("postal code is not valid for the country")
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// postalCodePatterns holds postal code formats for countries we ship to.
+// Countries without an entry only need a non-empty code.
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"CO": regexp.MustCompile(`^\d{6}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"AR": regexp.MustCompile(`^([A-Z]\d{4}[A-Z]{3}|\d{4})$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+}
+
+// AddressService defines the interface for a user's address book
+type AddressService interface {
+	ListAddresses(ctx context.Context, userID uuid.UUID) ([]domain.Address, error)
+	GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*domain.Address, error)
+	CreateAddress(ctx context.Context, userID uuid.UUID, address *domain.Address) error
+	UpdateAddress(ctx context.Context, userID uuid.UUID, address *domain.Address) error
+	DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error
+	SetDefaultAddress(ctx context.Context, userID, addressID uuid.UUID) (*domain.Address, error)
+}
+
+type addressService struct {
+	addressRepo repository.AddressRepository
+}
+
+// NewAddressService creates a new instance of AddressService
+func NewAddressService(addressRepo repository.AddressRepository) AddressService {
+	return &addressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func (s *addressService) ListAddresses(ctx context.Context, userID uuid.UUID) ([]domain.Address, error) {
+	addresses, err := s.addressRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses: %w", err)
+	}
+	return addresses, nil
+}
+
+func (s *addressService) GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*domain.Address, error) {
+	address, err := s.addressRepo.FindByID(ctx, addressID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+
+	// Other users' addresses are reported as missing
+	if address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+
+	return address, nil
+}
+
+func (s *addressService) CreateAddress(ctx context.Context, userID uuid.UUID, address *domain.Address) error {
+	if address == nil {
+		return ErrInvalidInput
+	}
+
+	address.ID = uuid.Nil
+	address.UserID = userID
+	if err := normalizeAddress(address); err != nil {
+		return err
+	}
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return fmt.Errorf("failed to create address: %w", err)
+	}
+
+	return nil
+}
+
+func (s *addressService) UpdateAddress(ctx context.Context, userID uuid.UUID, address *domain.Address) error {
+	if address == nil || address.ID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	existing, err := s.GetAddress(ctx, userID, address.ID)
+	if err != nil {
+		return err
+	}
+
+	address.UserID = userID
+	address.CreatedAt = existing.CreatedAt
+	if err := normalizeAddress(address); err != nil {
+		return err
+	}
+
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return fmt.Errorf("failed to update address: %w", err)
+	}
+
+	return nil
+}
+
+func (s *addressService) DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	if _, err := s.GetAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+
+	if err := s.addressRepo.Delete(ctx, addressID); err != nil {
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	return nil
+}
+
+func (s *addressService) SetDefaultAddress(ctx context.Context, userID, addressID uuid.UUID) (*domain.Address, error) {
+	if _, err := s.GetAddress(ctx, userID, addressID); err != nil {
+		return nil, err
+	}
+
+	if err := s.addressRepo.SetDefault(ctx, userID, addressID); err != nil {
+		return nil, fmt.Errorf("failed to set default address: %w", err)
+	}
+
+	return s.GetAddress(ctx, userID, addressID)
+}
+
+// normalizeAddress trims and canonicalizes address fields and validates
+// the postal code against the country's format
+func normalizeAddress(address *domain.Address) error {
+	address.Street = strings.TrimSpace(address.Street)
+	address.City = strings.TrimSpace(address.City)
+	address.State = strings.TrimSpace(address.State)
+	address.PostalCode = strings.ToUpper(strings.TrimSpace(address.PostalCode))
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+	address.Type = strings.ToLower(strings.TrimSpace(address.Type))
+
+	if address.Street == "" || address.City == "" || address.PostalCode == "" {
+		return ErrInvalidInput
+	}
+
+	if address.Type == "" {
+		address.Type = domain.AddressTypeShipping
+	}
+	if address.Type != domain.AddressTypeShipping && address.Type != domain.AddressTypeBilling {
+		return ErrInvalidAddressType
+	}
+
+	if !countryCodePattern.MatchString(address.Country) {
+		return ErrInvalidCountry
+	}
+
+	if pattern, ok := postalCodePatterns[address.Country]; ok && !pattern.MatchString(address.PostalCode) {
+		return ErrInvalidPostalCode
+	}
+
+	return nil
+}
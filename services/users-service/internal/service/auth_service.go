@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
@@ -16,31 +19,58 @@ import (
 )
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user with this email already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidInput       = errors.New("invalid input")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserAlreadyExists   = errors.New("user with this email already exists")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrInvalidToken        = errors.New("invalid or expired token")
 )
 
+// TokenPair is a short-lived access token plus the opaque refresh token used to renew it
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
 // AuthService defines the interface for authentication business logic
 type AuthService interface {
 	Register(ctx context.Context, email, password, fullName string) (*domain.User, error)
-	Login(ctx context.Context, email, password string) (string, *domain.User, error)
+	Login(ctx context.Context, email, password string) (*TokenPair, *domain.User, error)
 	ValidateToken(ctx context.Context, token string) (*customJWT.Claims, error)
-	RefreshToken(ctx context.Context, token string) (string, error)
-	Logout(ctx context.Context, userID uuid.UUID) error
+	RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, userID, sessionID uuid.UUID, allSessions bool) error
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	jwtManager *customJWT.JWTManager
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	roleRepo        repository.RoleRepository
+	jwtManager      *customJWT.JWTManager
+	refreshTokenTTL time.Duration
+	defaultRoles    []string
 }
 
-// NewAuthService creates a new instance of AuthService
-func NewAuthService(userRepo repository.UserRepository, jwtManager *customJWT.JWTManager) AuthService {
+// NewAuthService creates a new instance of AuthService.
+// defaultRoles are the role names attached to every newly registered user.
+func NewAuthService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	roleRepo repository.RoleRepository,
+	jwtManager *customJWT.JWTManager,
+	refreshTokenTTL time.Duration,
+	defaultRoles []string,
+) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		roleRepo:        roleRepo,
+		jwtManager:      jwtManager,
+		refreshTokenTTL: refreshTokenTTL,
+		defaultRoles:    defaultRoles,
 	}
 }
 
@@ -65,6 +95,16 @@ func (s *authService) Register(ctx context.Context, email, password, fullName st
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
+	// Resolve default roles before touching the users table
+	roles := make([]domain.Role, 0, len(s.defaultRoles))
+	for _, name := range s.defaultRoles {
+		role, err := s.roleRepo.FindByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default role %q: %w", name, err)
+		}
+		roles = append(roles, *role)
+	}
+
 	// Create user
 	user := &domain.User{
 		Email:        email,
@@ -72,70 +112,199 @@ func (s *authService) Register(ctx context.Context, email, password, fullName st
 		FullName:     fullName,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	// Create the user and attach default roles atomically so no user is left without roles
+	err = s.userRepo.Transaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		for _, role := range roles {
+			if err := txRepo.AssignRole(ctx, user.ID, role.ID); err != nil {
+				return fmt.Errorf("failed to assign role %q: %w", role.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Assign default "customer" role (assuming it exists)
-	// This is a simplified approach; in production, ensure the role exists first
-	// For now, we'll skip this and handle it in migrations or seed data
-
+	user.Roles = roles
 	return user, nil
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, *domain.User, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (*TokenPair, *domain.User, error) {
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil, ErrInvalidCredentials
+			return nil, nil, ErrInvalidCredentials
 		}
-		return "", nil, fmt.Errorf("failed to find user: %w", err)
+		return nil, nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Verify password
 	if !verifyPassword(user.PasswordHash, password) {
-		return "", nil, ErrInvalidCredentials
+		return nil, nil, ErrInvalidCredentials
 	}
 
-	// Extract role names
-	var roleNames []string
-	for _, role := range user.Roles {
-		roleNames = append(roleNames, role.Name)
-	}
-
-	// Generate JWT token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Email, roleNames)
+	// Every login starts a new refresh token family
+	tokens, err := s.issueTokens(ctx, user, uuid.New())
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, err
 	}
 
-	return token, user, nil
+	return tokens, user, nil
 }
 
 func (s *authService) ValidateToken(ctx context.Context, token string) (*customJWT.Claims, error) {
 	claims, err := s.jwtManager.ValidateToken(token)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	// Access tokens die with the session they were issued for
+	session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if session.IsRevoked() {
+		return nil, ErrSessionRevoked
 	}
+
 	return claims, nil
 }
 
-func (s *authService) RefreshToken(ctx context.Context, token string) (string, error) {
-	newToken, err := s.jwtManager.RefreshToken(token)
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessionRepo.FindByTokenHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	// A revoked token being presented again means it leaked: kill the whole family
+	if session.IsRevoked() {
+		if err := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session family: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if session.IsExpired() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Rotate: only one caller can win the revoke of the current token
+	active, err := s.sessionRepo.Revoke(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !active {
+		if err := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session family: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	// Reload the user so role and permission changes are picked up
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
 	if err != nil {
-		return "", fmt.Errorf("failed to refresh token: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
-	return newToken, nil
+
+	return s.issueTokens(ctx, user, session.FamilyID)
 }
 
-func (s *authService) Logout(ctx context.Context, userID uuid.UUID) error {
-	// In a real implementation, you might want to blacklist the token
-	// or delete sessions from a session store
-	// For now, this is a placeholder
+func (s *authService) Logout(ctx context.Context, userID, sessionID uuid.UUID, allSessions bool) error {
+	if allSessions {
+		if err := s.sessionRepo.RevokeByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		return nil
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+	if session.UserID != userID {
+		return nil
+	}
+
+	// Revoke the family so refresh tokens rotated from this login stop working too
+	if err := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
 	return nil
 }
 
+// issueTokens stores a new hashed refresh token in the given family and
+// signs an access token bound to it
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, familyID uuid.UUID) (*TokenPair, error) {
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	session := &domain.Session{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Extract role names and the effective permission set
+	roleNames, permissions := ResolveRoles(user.Roles)
+
+	// Generate JWT token
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, session.ID, user.Email, roleNames, permissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.jwtManager.TokenDuration(),
+	}, nil
+}
+
+// ResolveRoles returns the role names and the deduplicated union of the
+// permissions granted by those roles
+func ResolveRoles(roles []domain.Role) ([]string, []string) {
+	var roleNames []string
+	var permissions []string
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+		for _, permission := range role.PermissionList() {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return roleNames, permissions
+}
+
 // hashPassword hashes a password using bcrypt
 func hashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -148,6 +317,15 @@ func verifyPassword(hashedPassword, password string) bool {
 	return err == nil
 }
 
+// generateRefreshToken returns a random opaque token; only its hash is persisted
+func generateRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
 // hashToken creates a SHA-256 hash of a token for storage
 func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
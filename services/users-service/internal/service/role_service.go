@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound       = errors.New("role not found")
+	ErrRoleAlreadyExists  = errors.New("role with this name already exists")
+	ErrBuiltinRole        = errors.New("built-in roles cannot be deleted")
+	ErrInvalidPermission  = errors.New("permissions must look like resource:action")
+	ErrRoleAlreadyGranted = errors.New("user already has this role")
+)
+
+var (
+	roleNamePattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+	permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+)
+
+// RoleService defines the interface for role management.
+// Permission changes reach users the next time they obtain a token.
+type RoleService interface {
+	CreateRole(ctx context.Context, name string, permissions []string) (*domain.Role, error)
+	GetRole(ctx context.Context, id uuid.UUID) (*domain.Role, error)
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	UpdatePermissions(ctx context.Context, id uuid.UUID, permissions []string) (*domain.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	GrantRole(ctx context.Context, userID, roleID uuid.UUID) (*domain.User, error)
+	RevokeRole(ctx context.Context, userID, roleID uuid.UUID) (*domain.User, error)
+}
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+// NewRoleService creates a new instance of RoleService
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *roleService) CreateRole(ctx context.Context, name string, permissions []string) (*domain.Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, ErrInvalidInput
+	}
+
+	encoded, err := encodePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing role: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrRoleAlreadyExists
+	}
+
+	role := &domain.Role{
+		Name:        name,
+		Permissions: encoded,
+	}
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *roleService) GetRole(ctx context.Context, id uuid.UUID) (*domain.Role, error) {
+	role, err := s.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return role, nil
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (s *roleService) UpdatePermissions(ctx context.Context, id uuid.UUID, permissions []string) (*domain.Role, error) {
+	encoded, err := encodePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Permissions = encoded
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if role.Name == domain.RoleCustomer || role.Name == domain.RoleAdmin {
+		return ErrBuiltinRole
+	}
+
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleService) GrantRole(ctx context.Context, userID, roleID uuid.UUID) (*domain.User, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetRole(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	for _, role := range user.Roles {
+		if role.ID == roleID {
+			return nil, ErrRoleAlreadyGranted
+		}
+	}
+
+	if err := s.userRepo.AssignRole(ctx, userID, roleID); err != nil {
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	return s.findUser(ctx, userID)
+}
+
+func (s *roleService) RevokeRole(ctx context.Context, userID, roleID uuid.UUID) (*domain.User, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetRole(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.RevokeRole(ctx, userID, roleID); err != nil {
+		return nil, fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	return s.findUser(ctx, userID)
+}
+
+func (s *roleService) findUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// encodePermissions validates and deduplicates a permission list and
+// returns it as the JSON array stored in Role.Permissions
+func encodePermissions(permissions []string) (string, error) {
+	seen := make(map[string]bool)
+	cleaned := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.ToLower(strings.TrimSpace(permission))
+		if !permissionPattern.MatchString(permission) {
+			return "", ErrInvalidPermission
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			cleaned = append(cleaned, permission)
+		}
+	}
+
+	encoded, err := json.Marshal(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode permissions: %w", err)
+	}
+	return string(encoded), nil
+}
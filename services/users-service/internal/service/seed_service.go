@@ -4,62 +4,138 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
 	"gorm.io/gorm"
 )
 
-// SeedRoles creates default roles if they don't exist
+// SeedRoles creates the default roles if they don't exist. Roles that exist
+// are left alone, so permissions removed through the API stay removed.
 func SeedRoles(db *gorm.DB) error {
 	ctx := context.Background()
 
 	// Define default roles
-	customerPermissions, _ := json.Marshal([]string{
-		"books:read",
-		"wishlist:read",
-		"wishlist:write",
-		"orders:read",
-		"orders:write",
-		"profile:read",
-		"profile:write",
-	})
-
-	adminPermissions, _ := json.Marshal([]string{
-		"books:read",
-		"books:write",
-		"books:delete",
-		"users:read",
-		"users:write",
-		"orders:read",
-		"orders:write",
-		"orders:manage",
-		"logs:read",
-	})
-
-	roles := []domain.Role{
-		{
-			Name:        "customer",
-			Permissions: string(customerPermissions),
+	defaults := map[string][]string{
+		domain.RoleCustomer: {
+			"books:read",
+			"wishlist:read",
+			"wishlist:write",
+			"orders:read",
+			"orders:write",
+			"profile:read",
+			"profile:write",
 		},
-		{
-			Name:        "admin",
-			Permissions: string(adminPermissions),
+		domain.RoleAdmin: {
+			"books:read",
+			"books:write",
+			"books:delete",
+			"users:read",
+			"users:write",
+			"orders:read",
+			"orders:write",
+			"orders:manage",
+			"logs:read",
+			"profile:read",
+			"profile:write",
+			"wishlist:read",
+			"wishlist:write",
 		},
 	}
 
-	for _, role := range roles {
+	for _, name := range []string{domain.RoleCustomer, domain.RoleAdmin} {
 		var existing domain.Role
-		err := db.WithContext(ctx).Where("name = ?", role.Name).First(&existing).Error
+		err := db.WithContext(ctx).Where("name = ?", name).First(&existing).Error
 
-		if err != nil {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Role doesn't exist, create it
+		permissions, _ := json.Marshal(defaults[name])
+		role := domain.Role{Name: name, Permissions: string(permissions)}
+		if err := db.WithContext(ctx).Create(&role).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// permissionGrants add permissions to roles that already exist. Each grant
+// runs once and is recorded by version in schema_migrations, so a permission
+// removed after its grant ran stays removed. Roles SeedRoles creates already
+// have their defaults, so a grant only matters to older databases.
+var permissionGrants = []struct {
+	version     string
+	role        string
+	permissions []string
+}{
+	{
+		version:     "2026-10-admin-profile-wishlist",
+		role:        domain.RoleAdmin,
+		permissions: []string{"profile:read", "profile:write", "wishlist:read", "wishlist:write"},
+	},
+}
+
+// GrantRolePermissions applies the permission grants that haven't run yet
+func GrantRolePermissions(db *gorm.DB) error {
+	ctx := context.Background()
+
+	err := db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(100) PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`).Error
+	if err != nil {
+		return err
+	}
+
+	for _, grant := range permissionGrants {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Claiming the version first makes a concurrent start wait, then
+			// skip the grant
+			claim := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)
+				ON CONFLICT (version) DO NOTHING`, grant.version)
+			if claim.Error != nil {
+				return claim.Error
+			}
+			if claim.RowsAffected == 0 {
+				return nil
+			}
+
+			var role domain.Role
+			err := tx.Where("name = ?", grant.role).First(&role).Error
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// Role doesn't exist, create it
-				if err := db.WithContext(ctx).Create(&role).Error; err != nil {
-					return err
-				}
-			} else {
+				return nil
+			}
+			if err != nil {
 				return err
 			}
+
+			var granted []string
+			if role.Permissions != "" {
+				if err := json.Unmarshal([]byte(role.Permissions), &granted); err != nil {
+					return fmt.Errorf("failed to read permissions of role %q: %w", role.Name, err)
+				}
+			}
+			merged := granted
+			for _, permission := range grant.permissions {
+				if !slices.Contains(merged, permission) {
+					merged = append(merged, permission)
+				}
+			}
+			if len(merged) == len(granted) {
+				return nil
+			}
+			permissions, _ := json.Marshal(merged)
+			return tx.Model(&role).Update("permissions", string(permissions)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply permission grant %s: %w", grant.version, err)
 		}
 	}
 
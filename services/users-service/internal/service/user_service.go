@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/domain"
+	"github.com/youngermaster/my-distributed-bookstore/services/users-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidEmail       = errors.New("invalid email address")
+	ErrIncorrectPassword  = errors.New("current password is incorrect")
+	ErrPasswordUnchanged  = errors.New("new password must differ from the current one")
+	ErrEmptyProfileUpdate = errors.New("no profile fields to update")
+	ErrInvalidFullName    = errors.New("full name cannot be empty")
+)
+
+// ProfileUpdate holds the profile fields a user may change; nil fields are left untouched
+type ProfileUpdate struct {
+	FullName *string
+	Email    *string
+}
+
+// UserService defines the interface for self-service profile management
+type UserService interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) (*domain.User, error)
+	ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error
+}
+
+type userService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+}
+
+// NewUserService creates a new instance of UserService
+func NewUserService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) UserService {
+	return &userService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (s *userService) GetProfile(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) (*domain.User, error) {
+	if update.FullName == nil && update.Email == nil {
+		return nil, ErrEmptyProfileUpdate
+	}
+
+	user, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.FullName != nil {
+		fullName := strings.TrimSpace(*update.FullName)
+		if fullName == "" {
+			return nil, ErrInvalidFullName
+		}
+		user.FullName = fullName
+	}
+
+	if update.Email != nil {
+		email := strings.TrimSpace(*update.Email)
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			return nil, ErrInvalidEmail
+		}
+
+		// Check the new email isn't taken by someone else
+		if email != user.Email {
+			existing, err := s.userRepo.FindByEmail(ctx, email)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("failed to check existing user: %w", err)
+			}
+			if existing != nil {
+				return nil, ErrUserAlreadyExists
+			}
+			user.Email = email
+		}
+	}
+
+	if err := s.save(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	if currentPassword == "" || newPassword == "" {
+		return ErrInvalidInput
+	}
+
+	user, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !verifyPassword(user.PasswordHash, currentPassword) {
+		return ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = hashedPassword
+
+	if err := s.save(ctx, user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// Keep the caller's login alive and sign out every other device
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+	if session == nil || session.UserID != userID {
+		err = s.sessionRepo.RevokeByUserID(ctx, userID)
+	} else {
+		err = s.sessionRepo.RevokeOtherFamilies(ctx, userID, session.FamilyID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+// save persists the user's own columns without touching roles or addresses
+func (s *userService) save(ctx context.Context, user *domain.User) error {
+	row := *user
+	row.Roles = nil
+	row.Addresses = nil
+	if err := s.userRepo.Update(ctx, &row); err != nil {
+		return err
+	}
+	user.UpdatedAt = row.UpdatedAt
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package authclient is a Go client for the users service Auth gRPC API.
+// Other services use it to validate access tokens without knowing the JWT secret.
+package authclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authv1 "github.com/youngermaster/my-distributed-bookstore/services/users-service/proto/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("invalid or expired token")
+	ErrPermissionDenied = errors.New("insufficient permissions")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUnavailable      = errors.New("auth service unavailable")
+)
+
+// defaultTimeout bounds each call when the caller's context has no deadline
+const defaultTimeout = 3 * time.Second
+
+// Identity is the authenticated caller behind a validated access token
+type Identity struct {
+	UserID      uuid.UUID
+	SessionID   uuid.UUID
+	Email       string
+	Roles       []string
+	Permissions []string
+	ExpiresAt   time.Time
+}
+
+// HasPermission reports whether the identity holds any of the given permissions
+func (i *Identity) HasPermission(permissions ...string) bool {
+	for _, required := range permissions {
+		for _, granted := range i.Permissions {
+			if granted == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// User is a user profile with its current roles and permissions
+type User struct {
+	ID          uuid.UUID
+	Email       string
+	FullName    string
+	Roles       []string
+	Permissions []string
+	CreatedAt   time.Time
+}
+
+// Client calls the users service Auth gRPC API
+type Client struct {
+	conn *grpc.ClientConn
+	auth authv1.AuthClient
+}
+
+// New creates a client for the Auth service at addr (e.g. "users-service:9092").
+// Without options the connection is plaintext, which suits the internal network.
+func New(addr string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn: conn,
+		auth: authv1.NewAuthClient(conn),
+	}, nil
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ValidateToken validates an access token, with or without the "Bearer " prefix
+func (c *Client) ValidateToken(ctx context.Context, token string) (*Identity, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.auth.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: token})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	identity := &Identity{
+		Email:       resp.GetEmail(),
+		Roles:       resp.GetRoles(),
+		Permissions: resp.GetPermissions(),
+	}
+	identity.UserID, _ = uuid.Parse(resp.GetUserId())
+	identity.SessionID, _ = uuid.Parse(resp.GetSessionId())
+	if resp.GetExpiresAt() != nil {
+		identity.ExpiresAt = resp.GetExpiresAt().AsTime()
+	}
+	return identity, nil
+}
+
+// GetUser loads a user with the roles and permissions currently stored in the
+// users service. The call must carry the service token or an access token
+// granting users:read; see WithToken.
+func (c *Client) GetUser(ctx context.Context, userID uuid.UUID) (*User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.auth.GetUser(ctx, &authv1.GetUserRequest{UserId: userID.String()})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &User{
+		ID:          userID,
+		Email:       resp.GetEmail(),
+		FullName:    resp.GetFullName(),
+		Roles:       resp.GetRoles(),
+		Permissions: resp.GetPermissions(),
+		CreatedAt:   resp.GetCreatedAt().AsTime(),
+	}, nil
+}
+
+// CheckPermission validates the token and reports whether it grants permission
+func (c *Client) CheckPermission(ctx context.Context, token, permission string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.auth.CheckPermission(ctx, &authv1.CheckPermissionRequest{
+		Token:      token,
+		Permission: permission,
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+	return resp.GetAllowed(), nil
+}
+
+// WithToken returns a context whose calls present token, either the service
+// token or an access token, to methods that require one
+func WithToken(ctx context.Context, token string) context.Context {
+	token = strings.TrimPrefix(strings.TrimSpace(token), "Bearer ")
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// withTimeout applies defaultTimeout unless ctx already has a deadline
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, defaultTimeout)
+}
+
+// mapError converts gRPC status errors to the package's sentinel errors
+func mapError(err error) error {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		return ErrUnauthenticated
+	case codes.PermissionDenied:
+		return ErrPermissionDenied
+	case codes.NotFound:
+		return ErrUserNotFound
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return errors.Join(ErrUnavailable, err)
+	}
+	return err
+}
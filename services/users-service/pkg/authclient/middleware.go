@@ -0,0 +1,51 @@
+package authclient
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Middleware creates a Fiber middleware that validates the bearer token through
+// the Auth service. It stores the same locals as the users service's own
+// middleware: userID, sessionID, userEmail, userRoles and userPermissions.
+func Middleware(client *Client) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "No authorization header",
+			})
+		}
+
+		// Extract token from "Bearer <token>"
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid authorization format",
+			})
+		}
+
+		identity, err := client.ValidateToken(c.Context(), tokenString)
+		if err != nil {
+			if errors.Is(err, ErrUnauthenticated) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid or expired token",
+				})
+			}
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Authentication service unavailable",
+			})
+		}
+
+		// Store user info in context
+		c.Locals("userID", identity.UserID)
+		c.Locals("sessionID", identity.SessionID)
+		c.Locals("userEmail", identity.Email)
+		c.Locals("userRoles", identity.Roles)
+		c.Locals("userPermissions", identity.Permissions)
+
+		return c.Next()
+	}
+}
@@ -15,9 +15,11 @@ var (
 
 // Claims represents the JWT claims
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Roles  []string  `json:"roles"`
+	UserID      uuid.UUID `json:"user_id"`
+	SessionID   uuid.UUID `json:"sid"`
+	Email       string    `json:"email"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
 	jwt.RegisteredClaims
 }
 
@@ -35,12 +37,19 @@ func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
 	}
 }
 
-// GenerateToken generates a new JWT token for a user
-func (m *JWTManager) GenerateToken(userID uuid.UUID, email string, roles []string) (string, error) {
+// TokenDuration returns how long issued access tokens stay valid
+func (m *JWTManager) TokenDuration() time.Duration {
+	return m.tokenDuration
+}
+
+// GenerateToken generates a new JWT access token bound to a refresh session
+func (m *JWTManager) GenerateToken(userID, sessionID uuid.UUID, email string, roles, permissions []string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Roles:  roles,
+		UserID:      userID,
+		SessionID:   sessionID,
+		Email:       email,
+		Roles:       roles,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -76,13 +85,3 @@ func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
-
-// RefreshToken generates a new token with extended expiration
-func (m *JWTManager) RefreshToken(oldToken string) (string, error) {
-	claims, err := m.ValidateToken(oldToken)
-	if err != nil {
-		return "", err
-	}
-
-	return m.GenerateToken(claims.UserID, claims.Email, claims.Roles)
-}